@@ -0,0 +1,167 @@
+package rbtree
+
+import "golang.org/x/exp/constraints"
+
+// augmentation is the interface of data which is kept in every node and computed from node and its children.
+// pull is called for every node whose subtree is changed: bottom-up after inserting and deleting,
+// for both nodes of rotation and for all nodes built by build.
+// push is called for every node before its children are changed: top-down before inserting and deleting
+// and for both nodes of rotation, so lazy changes of subtree can be moved to children
+type augmentation[V constraints.Ordered] interface {
+	pull(n *node[V])
+	push(n *node[V])
+}
+
+// AggregateTree is a tree which keeps aggregate of every subtree (sum, count, min or max of values etc).
+// Aggregate of subtree is combine(aggregate of left subtree, node's element, aggregate of right subtree),
+// aggregate of empty subtree is empty. Aggregates are updated in O(log n) by every change of tree,
+// so all methods of Tree can be used.
+// combine should be a fold of monoid (e.g. left + value + right with identity 0):
+// aggregate of elements shouldn't depend on shape of tree
+type AggregateTree[V constraints.Ordered, A any] struct {
+	*Tree[V]
+	aggregator *aggregator[V, A]
+}
+
+// aggregator is the augmentation of AggregateTree
+type aggregator[V constraints.Ordered, A any] struct {
+	empty   A
+	combine func(left A, key V, value any, right A) A
+}
+
+// NewAggregateTree is a function for creation empty tree with aggregates
+// - param empty is aggregate of empty subtree
+// - param combine computes aggregate of subtree from aggregates of children and element of subtree's root
+// - param opts are optional settings of tree (WithAccessCheck etc)
+func NewAggregateTree[V constraints.Ordered, A any](empty A, combine func(left A, key V, value any, right A) A, opts ...Option) *AggregateTree[V, A] {
+	a := &aggregator[V, A]{empty: empty, combine: combine}
+	t := New[V](opts...)
+	t.aug = a
+
+	return &AggregateTree[V, A]{Tree: t, aggregator: a}
+}
+
+// AggregateAll is a function for getting aggregate of all elements of tree in O(1).
+func (t *AggregateTree[V, A]) AggregateAll() A {
+	t.guard.startRead()
+	defer t.guard.endRead()
+
+	return t.aggregator.of(t.root)
+}
+
+// Aggregate is a function for getting aggregate of elements with keys in range [lo, hi) in O(log n).
+// - params lo and hi should be `ordered type` (`int`, `string`, `float` etc)
+func (t *AggregateTree[V, A]) Aggregate(lo, hi V) A {
+	t.guard.startRead()
+	defer t.guard.endRead()
+
+	// the highest node in range divides it into suffix of its left subtree and prefix of its right subtree
+	n := t.root
+	for n != t.nilNode && (n.element.key < lo || n.element.key >= hi) {
+		if n.element.key < lo {
+			n = n.right
+			continue
+		}
+		n = n.left
+	}
+	if n == t.nilNode {
+		return t.aggregator.empty
+	}
+
+	return t.aggregator.combine(t.from(n.left, lo), n.element.key, n.element.value, t.before(n.right, hi))
+}
+
+// Split is a function for moving elements with keys >= key to new tree with the same aggregates (see Tree.Split).
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *AggregateTree[V, A]) Split(key V) *AggregateTree[V, A] {
+	return &AggregateTree[V, A]{Tree: t.Tree.Split(key), aggregator: t.aggregator}
+}
+
+// from - internal function for getting aggregate of elements of subtree n with keys >= lo
+func (t *AggregateTree[V, A]) from(n *node[V], lo V) A {
+	for n != t.nilNode && n.element.key < lo {
+		n = n.right
+	}
+	if n == t.nilNode {
+		return t.aggregator.empty
+	}
+
+	return t.aggregator.combine(t.from(n.left, lo), n.element.key, n.element.value, t.aggregator.of(n.right))
+}
+
+// before - internal function for getting aggregate of elements of subtree n with keys < hi
+func (t *AggregateTree[V, A]) before(n *node[V], hi V) A {
+	for n != t.nilNode && n.element.key >= hi {
+		n = n.left
+	}
+	if n == t.nilNode {
+		return t.aggregator.empty
+	}
+
+	return t.aggregator.combine(t.aggregator.of(n.left), n.element.key, n.element.value, t.before(n.right, hi))
+}
+
+func (a *aggregator[V, A]) pull(n *node[V]) {
+	// aggregate is kept by pointer and updated in place, so it isn't boxed by every pull
+	p, ok := n.agg().(*A)
+	if !ok {
+		p = new(A)
+		n.setAgg(p)
+	}
+	*p = a.combine(a.of(n.left), n.element.key, n.element.value, a.of(n.right))
+}
+
+func (a *aggregator[V, A]) push(n *node[V]) {}
+
+// of - internal function for getting aggregate of subtree, aggregate of nilNode is empty
+func (a *aggregator[V, A]) of(n *node[V]) A {
+	p, ok := n.agg().(*A)
+	if !ok {
+		return a.empty
+	}
+
+	return *p
+}
+
+// pull - internal function for recomputing merkle hash and aggregate of n's subtree from its children
+func (t *Tree[V]) pull(n *node[V]) {
+	if t.merkle {
+		pullMerkle(n)
+	}
+	if t.aug != nil {
+		t.aug.pull(n)
+	}
+}
+
+// pullPath - internal function for recomputing merkle hashes and aggregates from n to root
+func (t *Tree[V]) pullPath(n *node[V]) {
+	if !t.merkle && t.aug == nil {
+		return
+	}
+
+	for ; n != t.nilNode; n = n.parent {
+		t.pull(n)
+	}
+}
+
+// push - internal function for moving lazy changes of n to its children
+func (t *Tree[V]) push(n *node[V]) {
+	if t.aug != nil {
+		t.aug.push(n)
+	}
+}
+
+// pushPath - internal function for moving lazy changes of all nodes from root to n (inclusive) to their children
+func (t *Tree[V]) pushPath(n *node[V]) {
+	if t.aug == nil {
+		return
+	}
+
+	var path []*node[V]
+	for ; n != t.nilNode; n = n.parent {
+		path = append(path, n)
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		t.aug.push(path[i])
+	}
+}
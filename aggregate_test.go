@@ -0,0 +1,132 @@
+package rbtree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// sumCount is the aggregate of test: sum and count of values
+type sumCount struct {
+	sum, count int
+}
+
+func newSumCountTree() *AggregateTree[int, sumCount] {
+	return NewAggregateTree[int](sumCount{}, func(left sumCount, key int, value any, right sumCount) sumCount {
+		return sumCount{
+			sum:   left.sum + value.(int) + right.sum,
+			count: left.count + 1 + right.count,
+		}
+	})
+}
+
+func TestAggregateTree_Aggregate(t1 *testing.T) {
+	t := newSumCountTree()
+	want := map[int]int{}
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 2000; i++ {
+		key := rng.Intn(300)
+		if rng.Intn(3) == 0 {
+			t.Delete(key)
+			delete(want, key)
+		} else {
+			value := rng.Intn(100)
+			t.Insert(key, value)
+			want[key] = value
+		}
+		checkAggregates(t1, t.Tree, t.root)
+
+		lo := rng.Intn(320) - 10
+		hi := lo + rng.Intn(100)
+		wantRange := sumCount{}
+		for key, value := range want {
+			if key >= lo && key < hi {
+				wantRange.sum += value
+				wantRange.count++
+			}
+		}
+		if got := t.Aggregate(lo, hi); got != wantRange {
+			t1.Fatalf("Aggregate(%v, %v) = %v, want %v", lo, hi, got, wantRange)
+		}
+	}
+
+	if got := t.AggregateAll(); got.count != len(want) {
+		t1.Errorf("AggregateAll() = %v, want count %v", got, len(want))
+	}
+}
+
+func TestAggregateTree_SplitJoin(t1 *testing.T) {
+	t := newSumCountTree()
+	for i := 0; i < 100; i++ {
+		t.Insert(i, i)
+	}
+
+	right := t.Split(50)
+	checkAggregates(t1, t.Tree, t.root)
+	checkAggregates(t1, right.Tree, right.root)
+	if got, want := t.AggregateAll(), (sumCount{sum: 1225, count: 50}); got != want {
+		t1.Errorf("AggregateAll() after Split = %v, want %v", got, want)
+	}
+
+	if got, want := right.AggregateAll(), (sumCount{sum: 3725, count: 50}); got != want {
+		t1.Errorf("AggregateAll() of split tree = %v, want %v", got, want)
+	}
+
+	right.Insert(100, 100)
+	mustNoErr(t1, t.Join(right.Tree))
+	checkAggregates(t1, t.Tree, t.root)
+	if got, want := t.Aggregate(40, 200), (sumCount{sum: 4170 + 100, count: 61}); got != want {
+		t1.Errorf("Aggregate(40, 200) after Join = %v, want %v", got, want)
+	}
+}
+
+func TestAggregateTree_Aggregate_empty(t1 *testing.T) {
+	t := newSumCountTree()
+	if got := t.Aggregate(0, 10); got != (sumCount{}) {
+		t1.Errorf("Aggregate() of empty tree = %v", got)
+	}
+
+	t.Insert(5, 5)
+	tests := []struct {
+		name   string
+		lo, hi int
+		want   sumCount
+	}{
+		{name: "range before key", lo: 0, hi: 5, want: sumCount{}},
+		{name: "range after key", lo: 6, hi: 10, want: sumCount{}},
+		{name: "range with key", lo: 5, hi: 6, want: sumCount{sum: 5, count: 1}},
+		{name: "inverted range", lo: 10, hi: 0, want: sumCount{}},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			if got := t.Aggregate(tt.lo, tt.hi); got != tt.want {
+				t1.Errorf("Aggregate(%v, %v) = %v, want %v", tt.lo, tt.hi, got, tt.want)
+			}
+		})
+	}
+}
+
+// checkAggregates checks aggregates of all nodes of subtree n and returns aggregate of subtree
+func checkAggregates(t1 *testing.T, t *Tree[int], n *node[int]) sumCount {
+	if n == t.nilNode {
+		return sumCount{}
+	}
+
+	left, right := checkAggregates(t1, t, n.left), checkAggregates(t1, t, n.right)
+	want := sumCount{sum: left.sum + n.element.value.(int) + right.sum, count: left.count + 1 + right.count}
+	if got := *n.agg().(*sumCount); got != want {
+		t1.Fatalf("node %v has aggregate %v, want %v", n.element.key, got, want)
+	}
+
+	return want
+}
+
+func BenchmarkAggregateTree_Insert(b *testing.B) {
+	keys := rand.New(rand.NewSource(1)).Perm(b.N)
+	t := newSumCountTree()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for _, key := range keys {
+		t.Insert(key, key)
+	}
+}
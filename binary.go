@@ -0,0 +1,305 @@
+package rbtree
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+
+	"golang.org/x/exp/constraints"
+)
+
+// binaryMagic is the header of binary format of tree
+const binaryMagic = "RBTR"
+
+// binaryVersion is the version of binary format of tree
+const binaryVersion byte = 1
+
+// maxElementPartLen is max length of encoded key or value (or of replication frame's payload),
+// bigger length means corrupted data
+const maxElementPartLen = 256 << 20
+
+// codecs is the structure of tree's settings for binary encoding
+type codecs[V constraints.Ordered] struct {
+	keys   KeyCodec[V]
+	values ValueCodec
+}
+
+// SetCodecs is a function for setting codecs of keys and values used by binary encoding of tree.
+// OrderedKeyCodec and GobValueCodec are used by default. Merkle hashes are recomputed in O(n).
+// - param keys is codec of keys, default codec is used if it's nil
+// - param values is codec of values, default codec is used if it's nil
+func (t *Tree[V]) SetCodecs(keys KeyCodec[V], values ValueCodec) {
+	t.codecs = newCodecs(keys, values)
+	if t.merkle {
+		t.rehash(t.root)
+	}
+}
+
+// MarshalBinary is a function for encoding tree to binary format (encoding.BinaryMarshaler)
+func (t *Tree[V]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := t.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary is a function for decoding tree from binary format (encoding.BinaryUnmarshaler).
+// All elements of tree are replaced by decoded elements.
+func (t *Tree[V]) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	if _, err := t.ReadFrom(r); err != nil {
+		return err
+	}
+	if r.Len() != 0 {
+		return errors.New(fmt.Sprintf("%v unexpected bytes after tree", r.Len()))
+	}
+
+	return nil
+}
+
+// WriteTo is a function for writing tree to w in binary format (io.WriterTo).
+// Format: header "RBTR", version byte, count of elements (uvarint),
+// elements in key order (uvarint length and bytes of key, uvarint length and bytes of value)
+// and CRC32 (IEEE, big endian) of all previous bytes.
+func (t *Tree[V]) WriteTo(w io.Writer) (int64, error) {
+	t.guard.startRead()
+	defer t.guard.endRead()
+
+	cw := &checksumWriter{w: w, crc: crc32.NewIEEE()}
+	c := t.getCodecs()
+
+	b := append([]byte(binaryMagic), binaryVersion)
+	b = binary.AppendUvarint(b, uint64(t.size))
+	if err := cw.write(b); err != nil {
+		return cw.n, err
+	}
+
+	var err error
+	t.Ascend(func(key V, value any) bool {
+		b, err = appendElement(b[:0], c, key, value)
+		if err == nil {
+			err = cw.write(b)
+		}
+		return err == nil
+	})
+	if err != nil {
+		return cw.n, err
+	}
+
+	err = cw.write(binary.BigEndian.AppendUint32(b[:0], cw.crc.Sum32()))
+
+	return cw.n, err
+}
+
+// ReadFrom is a function for reading tree from r in binary format written by WriteTo (io.ReaderFrom).
+// All elements of tree are replaced by read elements, tree is built in linear time.
+// ReadFrom doesn't read bytes after tree from r.
+func (t *Tree[V]) ReadFrom(r io.Reader) (int64, error) {
+	cr := &checksumReader{r: r, crc: crc32.NewIEEE()}
+	c := t.getCodecs()
+
+	header := make([]byte, len(binaryMagic)+1)
+	if err := cr.readFull(header); err != nil {
+		return cr.n, err
+	}
+	if string(header[:len(binaryMagic)]) != binaryMagic {
+		return cr.n, errors.New("invalid header of binary format")
+	}
+	if header[len(binaryMagic)] != binaryVersion {
+		return cr.n, errors.New(fmt.Sprintf("unsupported version %v of binary format", header[len(binaryMagic)]))
+	}
+
+	count, err := binary.ReadUvarint(cr)
+	if err != nil {
+		return cr.n, err
+	}
+
+	elements := make([]element[V], 0, minCapacity(count))
+	for i := uint64(0); i < count; i++ {
+		e, err := readElement(cr, c)
+		if err != nil {
+			return cr.n, err
+		}
+		if i > 0 && e.key <= elements[len(elements)-1].key {
+			return cr.n, errors.New(fmt.Sprintf("key %v is out of order", e.key))
+		}
+		elements = append(elements, e)
+	}
+
+	sum := cr.crc.Sum32()
+	b := make([]byte, 4)
+	if err := cr.readFull(b); err != nil {
+		return cr.n, err
+	}
+	if binary.BigEndian.Uint32(b) != sum {
+		return cr.n, errors.New("checksum mismatch")
+	}
+
+	t.init()
+	t.guard.startWrite()
+	defer t.guard.endWrite()
+	t.build(elements)
+
+	return cr.n, nil
+}
+
+// minCapacity - internal function for limiting capacity allocated for count from untrusted data
+func minCapacity(count uint64) uint64 {
+	if count > 1<<16 {
+		return 1 << 16
+	}
+
+	return count
+}
+
+// getCodecs - internal function for getting codecs set by SetCodecs or default codecs
+func (t *Tree[V]) getCodecs() *codecs[V] {
+	if t.codecs == nil {
+		return &codecs[V]{keys: OrderedKeyCodec[V]{}, values: GobValueCodec{}}
+	}
+
+	return t.codecs
+}
+
+// newCodecs - internal function for creation codecs, default codecs are used instead of nil
+func newCodecs[V constraints.Ordered](keys KeyCodec[V], values ValueCodec) *codecs[V] {
+	if keys == nil {
+		keys = OrderedKeyCodec[V]{}
+	}
+	if values == nil {
+		values = GobValueCodec{}
+	}
+
+	return &codecs[V]{keys: keys, values: values}
+}
+
+// init - internal function for initialization of zero Tree
+func (t *Tree[V]) init() {
+	if t.nilNode == nil {
+		t.nilNode = &node[V]{color: black}
+		t.root = t.nilNode
+	}
+}
+
+// appendElement - internal function for encoding element with length prefixes of key and value
+func appendElement[V constraints.Ordered](b []byte, c *codecs[V], key V, value any) ([]byte, error) {
+	kb, err := c.keys.AppendKey(nil, key)
+	if err != nil {
+		return b, err
+	}
+	vb, err := c.values.AppendValue(nil, value)
+	if err != nil {
+		return b, err
+	}
+
+	b = binary.AppendUvarint(b, uint64(len(kb)))
+	b = append(b, kb...)
+	b = binary.AppendUvarint(b, uint64(len(vb)))
+
+	return append(b, vb...), nil
+}
+
+// readElement - internal function for decoding element encoded by appendElement
+func readElement[V constraints.Ordered](r *checksumReader, c *codecs[V]) (element[V], error) {
+	var e element[V]
+	kb, err := r.readBytes()
+	if err != nil {
+		return e, err
+	}
+	if e.key, err = c.keys.DecodeKey(kb); err != nil {
+		return e, err
+	}
+
+	vb, err := r.readBytes()
+	if err != nil {
+		return e, err
+	}
+	e.value, err = c.values.DecodeValue(vb)
+
+	return e, err
+}
+
+// checksumWriter is io.Writer which counts written bytes and their checksum
+type checksumWriter struct {
+	w   io.Writer
+	crc hash.Hash32
+	n   int64
+}
+
+func (w *checksumWriter) write(b []byte) error {
+	n, err := w.w.Write(b)
+	w.n += int64(n)
+	w.crc.Write(b[:n])
+
+	return err
+}
+
+// checksumReader is io.ByteReader which counts read bytes and their checksum.
+// It never reads more bytes from r than requested
+type checksumReader struct {
+	r   io.Reader
+	crc hash.Hash32
+	n   int64
+	buf [1]byte
+}
+
+func (r *checksumReader) ReadByte() (byte, error) {
+	if err := r.readFull(r.buf[:]); err != nil {
+		return 0, err
+	}
+
+	return r.buf[0], nil
+}
+
+func (r *checksumReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	r.n += int64(n)
+	r.crc.Write(b[:n])
+
+	return n, err
+}
+func (r *checksumReader) readFull(b []byte) error {
+	n, err := io.ReadFull(r.r, b)
+	r.n += int64(n)
+	r.crc.Write(b[:n])
+	if err == io.EOF && len(b) > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+func (r *checksumReader) readBytes() ([]byte, error) {
+	l, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if l > maxElementPartLen {
+		return nil, errors.New(fmt.Sprintf("element's length %v is too big", l))
+	}
+
+	// length of data in memory is known, so length of element can't be bigger
+	if sized, ok := r.r.(interface{ Len() int }); ok {
+		if l > uint64(sized.Len()) {
+			return nil, errors.New(fmt.Sprintf("element's length %v is bigger than %v remaining bytes", l, sized.Len()))
+		}
+		b := make([]byte, l)
+		err = r.readFull(b)
+		return b, err
+	}
+
+	// length of stream is unknown, so buffer grows only with really read bytes
+	var buf bytes.Buffer
+	n, err := io.CopyN(&buf, r, int64(l))
+	if err == io.EOF && n < int64(l) {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return buf.Bytes(), err
+}
@@ -0,0 +1,171 @@
+package rbtree
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*Tree[int])(nil)
+	_ encoding.BinaryUnmarshaler = (*Tree[int])(nil)
+	_ io.WriterTo                = (*Tree[int])(nil)
+	_ io.ReaderFrom              = (*Tree[int])(nil)
+)
+
+func TestTree_MarshalBinary(t1 *testing.T) {
+	for _, n := range []int{0, 1, 2, 7, 100} {
+		t1.Run(fmt.Sprintf("%v elements", n), func(t1 *testing.T) {
+			t := New[int]()
+			for i := 0; i < n; i++ {
+				t.Insert(i*3-50, fmt.Sprint(i))
+			}
+			t.Insert(1000, nil)
+
+			data, err := t.MarshalBinary()
+			if err != nil {
+				t1.Fatalf("MarshalBinary() error = %v", err)
+			}
+
+			var got Tree[int]
+			if err := got.UnmarshalBinary(data); err != nil {
+				t1.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+			checkTreeProperties(t1, &got)
+			if !reflect.DeepEqual(got.elements(), t.elements()) {
+				t1.Errorf("UnmarshalBinary() = %v, want %v", got.elements(), t.elements())
+			}
+		})
+	}
+}
+
+func TestTree_ReadFrom_errors(t1 *testing.T) {
+	t := getTree([]int{22, 8, 4})
+	data, _ := t.MarshalBinary()
+
+	corrupt := func(i int) []byte {
+		b := append([]byte(nil), data...)
+		b[i] ^= 0xff
+		return b
+	}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "wrong header", data: corrupt(0)},
+		{name: "wrong version", data: corrupt(4)},
+		{name: "wrong checksum", data: corrupt(len(data) - 1)},
+		{name: "corrupted element", data: corrupt(len(data) - 6)},
+		{name: "truncated", data: data[:len(data)-2]},
+		{name: "trailing bytes", data: append(append([]byte(nil), data...), 0)},
+		{name: "empty", data: nil},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			if err := New[int]().UnmarshalBinary(tt.data); err == nil {
+				t1.Errorf("UnmarshalBinary() without error")
+			}
+		})
+	}
+}
+
+func TestTree_ReadFrom_hugeLength(t1 *testing.T) {
+	data := append([]byte(binaryMagic), binaryVersion)
+	data = binary.AppendUvarint(data, 1)
+	data = binary.AppendUvarint(data, maxElementPartLen)
+	data = append(data, "short key"...)
+
+	tests := []struct {
+		name string
+		r    io.Reader
+	}{
+		{name: "data in memory", r: bytes.NewReader(data)},
+		{name: "stream", r: io.MultiReader(bytes.NewReader(data))},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			var before, after runtime.MemStats
+			runtime.ReadMemStats(&before)
+			_, err := New[string]().ReadFrom(tt.r)
+			runtime.ReadMemStats(&after)
+			if err == nil {
+				t1.Fatalf("ReadFrom() without error")
+			}
+			if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+				t1.Errorf("ReadFrom() allocated %v bytes", allocated)
+			}
+		})
+	}
+}
+
+func TestTree_ReadFrom_stream(t1 *testing.T) {
+	t := New[string]()
+	t.Insert("b", 2.5)
+	t.Insert("a", []byte("bytes"))
+
+	var buf bytes.Buffer
+	written, err := t.WriteTo(&buf)
+	if err != nil {
+		t1.Fatalf("WriteTo() error = %v", err)
+	}
+	buf.WriteString("tail")
+
+	got := New[string]()
+	read, err := got.ReadFrom(&buf)
+	if err != nil || read != written {
+		t1.Fatalf("ReadFrom() = %v, %v, want %v", read, err, written)
+	}
+	if buf.String() != "tail" {
+		t1.Errorf("ReadFrom() read bytes after tree")
+	}
+	if !reflect.DeepEqual(got.elements(), t.elements()) {
+		t1.Errorf("ReadFrom() = %v, want %v", got.elements(), t.elements())
+	}
+}
+
+type intStringCodec struct{}
+
+func (intStringCodec) AppendValue(b []byte, value any) ([]byte, error) {
+	i, ok := value.(int)
+	if !ok {
+		return b, errors.New("value is not int")
+	}
+	return strconv.AppendInt(b, int64(i), 10), nil
+}
+
+func (intStringCodec) DecodeValue(b []byte) (any, error) {
+	return strconv.Atoi(string(b))
+}
+
+func TestTree_SetCodecs(t1 *testing.T) {
+	t := getTree([]int{22, 8, 4})
+	t.SetCodecs(nil, intStringCodec{})
+	data, err := t.MarshalBinary()
+	if err != nil {
+		t1.Fatalf("MarshalBinary() error = %v", err)
+	}
+	if !bytes.Contains(data, []byte("22")) {
+		t1.Errorf("value codec wasn't used")
+	}
+
+	got := New[int]()
+	got.SetCodecs(nil, intStringCodec{})
+	if err := got.UnmarshalBinary(data); err != nil {
+		t1.Fatalf("UnmarshalBinary() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.elements(), t.elements()) {
+		t1.Errorf("UnmarshalBinary() = %v, want %v", got.elements(), t.elements())
+	}
+
+	t.Insert(1, "not int")
+	if _, err := t.MarshalBinary(); err == nil {
+		t1.Errorf("MarshalBinary() with codec error without error")
+	}
+}
@@ -0,0 +1,107 @@
+package rbtree
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+
+	"golang.org/x/exp/constraints"
+)
+
+// KeyCodec is an interface for encoding and decoding keys of tree
+type KeyCodec[V constraints.Ordered] interface {
+	// AppendKey appends encoded key to b and returns extended slice
+	AppendKey(b []byte, key V) ([]byte, error)
+	// DecodeKey decodes key from all bytes of b
+	DecodeKey(b []byte) (V, error)
+}
+
+// ValueCodec is an interface for encoding and decoding values of tree
+type ValueCodec interface {
+	// AppendValue appends encoded value to b and returns extended slice
+	AppendValue(b []byte, value any) ([]byte, error)
+	// DecodeValue decodes value from all bytes of b
+	DecodeValue(b []byte) (any, error)
+}
+
+// OrderedKeyCodec is default KeyCodec. It encodes integers as varints,
+// floats as 8 bytes and strings as is
+type OrderedKeyCodec[V constraints.Ordered] struct{}
+
+// GobValueCodec is default ValueCodec. It encodes values by encoding/gob,
+// so types of values except basic types should be registered by gob.Register
+type GobValueCodec struct{}
+
+// AppendKey appends encoded key to b and returns extended slice
+func (OrderedKeyCodec[V]) AppendKey(b []byte, key V) ([]byte, error) {
+	v := reflect.ValueOf(key)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.AppendVarint(b, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return binary.AppendUvarint(b, v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return binary.BigEndian.AppendUint64(b, math.Float64bits(v.Float())), nil
+	case reflect.String:
+		return append(b, v.String()...), nil
+	}
+
+	return b, errors.New(fmt.Sprintf("unsupported key type %T", key))
+}
+
+// DecodeKey decodes key from all bytes of b
+func (OrderedKeyCodec[V]) DecodeKey(b []byte) (V, error) {
+	var key V
+	v := reflect.ValueOf(&key).Elem()
+	n := len(b)
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		i, n = binary.Varint(b)
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		u, n = binary.Uvarint(b)
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		if len(b) != 8 {
+			return key, errors.New("invalid float key")
+		}
+		v.SetFloat(math.Float64frombits(binary.BigEndian.Uint64(b)))
+	case reflect.String:
+		v.SetString(string(b))
+	default:
+		return key, errors.New(fmt.Sprintf("unsupported key type %T", key))
+	}
+
+	if n != len(b) {
+		return key, errors.New("invalid integer key")
+	}
+
+	return key, nil
+}
+
+// AppendValue appends encoded value to b and returns extended slice
+func (GobValueCodec) AppendValue(b []byte, value any) ([]byte, error) {
+	buf := bytes.NewBuffer(b)
+	if err := gob.NewEncoder(buf).Encode(&value); err != nil {
+		return b, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// DecodeValue decodes value from all bytes of b
+func (GobValueCodec) DecodeValue(b []byte) (any, error) {
+	var value any
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
@@ -0,0 +1,38 @@
+package rbtree
+
+import (
+	"testing"
+
+	"golang.org/x/exp/constraints"
+)
+
+func TestOrderedKeyCodec(t1 *testing.T) {
+	checkKeyCodec(t1, []int{0, -1, 1, -1 << 40, 1 << 62})
+	checkKeyCodec(t1, []uint8{0, 1, 255})
+	checkKeyCodec(t1, []float64{0, -1.5, 3.25e100})
+	checkKeyCodec(t1, []string{"", "key", "ключ"})
+
+	type level int
+	checkKeyCodec(t1, []level{-3, 7})
+
+	if _, err := (OrderedKeyCodec[int]{}).DecodeKey([]byte{0x80}); err == nil {
+		t1.Errorf("DecodeKey() of broken varint without error")
+	}
+	if _, err := (OrderedKeyCodec[float32]{}).DecodeKey([]byte{1, 2}); err == nil {
+		t1.Errorf("DecodeKey() of short float without error")
+	}
+}
+
+func checkKeyCodec[V constraints.Ordered](t *testing.T, keys []V) {
+	var c OrderedKeyCodec[V]
+	for _, key := range keys {
+		b, err := c.AppendKey(nil, key)
+		if err != nil {
+			t.Fatalf("AppendKey(%v) error = %v", key, err)
+		}
+		got, err := c.DecodeKey(b)
+		if err != nil || got != key {
+			t.Errorf("DecodeKey() = %v, %v, want %v", got, err, key)
+		}
+	}
+}
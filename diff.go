@@ -0,0 +1,158 @@
+package rbtree
+
+import (
+	"reflect"
+
+	"golang.org/x/exp/constraints"
+)
+
+// DiffKind is a kind of difference between two trees
+type DiffKind int
+
+const (
+	// Added - element exists in new tree only
+	Added DiffKind = iota + 1
+	// Removed - element exists in old tree only
+	Removed
+	// Changed - element exists in both trees with different values
+	Changed
+)
+
+// DiffEvent is the structure of one difference between two trees.
+// Old is nil for Added element, New is nil for Removed element
+type DiffEvent[V constraints.Ordered] struct {
+	Kind DiffKind
+	Key  V
+	Old  any
+	New  any
+}
+
+// pframe is the structure of persistent tree iterator's stack item:
+// element of node if expanded is true, else - the whole subtree of node
+type pframe[V constraints.Ordered] struct {
+	n        *pnode[V]
+	expanded bool
+}
+
+// Diff is a function for comparing old tree a with new tree b by merge walk in O(len(a) + len(b)).
+// fn is called for every difference in key order, iteration stops when fn returns false.
+// - param equal compares values of elements with the same key, reflect.DeepEqual is used if it's nil
+func Diff[V constraints.Ordered](a, b *Tree[V], equal func(old, new any) bool, fn func(e DiffEvent[V]) bool) {
+	a.guard.startRead()
+	defer a.guard.endRead()
+	b.guard.startRead()
+	defer b.guard.endRead()
+
+	if equal == nil {
+		equal = reflect.DeepEqual
+	}
+
+	var na, nb *node[V]
+	if a.root != a.nilNode {
+		na = a.min(a.root)
+	}
+	if b.root != b.nilNode {
+		nb = b.min(b.root)
+	}
+
+	for na != nil || nb != nil {
+		switch {
+		case na == nil || nb != nil && nb.element.key < na.element.key:
+			if !fn(DiffEvent[V]{Kind: Added, Key: nb.element.key, New: nb.element.value}) {
+				return
+			}
+			nb = b.successor(nb)
+		case nb == nil || na.element.key < nb.element.key:
+			if !fn(DiffEvent[V]{Kind: Removed, Key: na.element.key, Old: na.element.value}) {
+				return
+			}
+			na = a.successor(na)
+		default:
+			if !equal(na.element.value, nb.element.value) &&
+				!fn(DiffEvent[V]{Kind: Changed, Key: na.element.key, Old: na.element.value, New: nb.element.value}) {
+				return
+			}
+			na, nb = a.successor(na), b.successor(nb)
+		}
+	}
+}
+
+// DiffSnapshots is a function for comparing old snapshot a with new snapshot b of the same SnapshotTree.
+// Snapshots share all subtrees which weren't changed between them, shared subtrees are skipped without visiting,
+// so snapshots which differ by d changes are compared in O(d log n).
+// fn is called for every difference in key order, iteration stops when fn returns false.
+// - param equal compares values of elements with the same key, reflect.DeepEqual is used if it's nil
+func DiffSnapshots[V constraints.Ordered](a, b *Snapshot[V], equal func(old, new any) bool, fn func(e DiffEvent[V]) bool) {
+	if equal == nil {
+		equal = reflect.DeepEqual
+	}
+
+	sa, sb := pPush(nil, a.root), pPush(nil, b.root)
+	for {
+		// expand the bigger subtree until both iterators are at elements:
+		// subtree shared by both snapshots is at the top of both stacks at the same time
+		for {
+			if len(sa) > 0 && len(sb) > 0 && sa[len(sa)-1] == sb[len(sb)-1] && !sa[len(sa)-1].expanded {
+				sa, sb = sa[:len(sa)-1], sb[:len(sb)-1]
+				continue
+			}
+			if len(sa) > 0 && !sa[len(sa)-1].expanded &&
+				(len(sb) == 0 || sb[len(sb)-1].expanded || sa[len(sa)-1].n.size >= sb[len(sb)-1].n.size) {
+				sa = pExpand(sa)
+				continue
+			}
+			if len(sb) > 0 && !sb[len(sb)-1].expanded {
+				sb = pExpand(sb)
+				continue
+			}
+			break
+		}
+
+		var ea, eb *element[V]
+		if len(sa) > 0 {
+			ea = &sa[len(sa)-1].n.element
+		}
+		if len(sb) > 0 {
+			eb = &sb[len(sb)-1].n.element
+		}
+
+		switch {
+		case ea == nil && eb == nil:
+			return
+		case ea == nil || eb != nil && eb.key < ea.key:
+			if !fn(DiffEvent[V]{Kind: Added, Key: eb.key, New: eb.value}) {
+				return
+			}
+			sb = sb[:len(sb)-1]
+		case eb == nil || ea.key < eb.key:
+			if !fn(DiffEvent[V]{Kind: Removed, Key: ea.key, Old: ea.value}) {
+				return
+			}
+			sa = sa[:len(sa)-1]
+		default:
+			if ea != eb && !equal(ea.value, eb.value) &&
+				!fn(DiffEvent[V]{Kind: Changed, Key: ea.key, Old: ea.value, New: eb.value}) {
+				return
+			}
+			sa, sb = sa[:len(sa)-1], sb[:len(sb)-1]
+		}
+	}
+}
+
+// pPush - internal function for pushing not expanded subtree n to iterator's stack
+func pPush[V constraints.Ordered](stack []pframe[V], n *pnode[V]) []pframe[V] {
+	if n == nil {
+		return stack
+	}
+
+	return append(stack, pframe[V]{n: n})
+}
+
+// pExpand - internal function for replacing subtree at the top of stack by its left subtree, element and right subtree
+func pExpand[V constraints.Ordered](stack []pframe[V]) []pframe[V] {
+	n := stack[len(stack)-1].n
+	stack = pPush(stack[:len(stack)-1], n.right)
+	stack = append(stack, pframe[V]{n: n, expanded: true})
+
+	return pPush(stack, n.left)
+}
@@ -0,0 +1,135 @@
+package rbtree
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t1 *testing.T) {
+	tests := []struct {
+		name  string
+		old   []int
+		new   []int
+		equal func(old, new any) bool
+		want  []DiffEvent[int]
+	}{
+		{name: "empty trees", want: nil},
+		{
+			name: "added elements",
+			new:  []int{1, 2},
+			want: []DiffEvent[int]{{Kind: Added, Key: 1, New: 1}, {Kind: Added, Key: 2, New: 2}},
+		},
+		{
+			name: "removed elements",
+			old:  []int{1, 2},
+			want: []DiffEvent[int]{{Kind: Removed, Key: 1, Old: 1}, {Kind: Removed, Key: 2, Old: 2}},
+		},
+		{name: "equal trees", old: []int{1, 2, 3}, new: []int{3, 2, 1}, want: nil},
+		{
+			name: "mixed changes",
+			old:  []int{1, 3, 5, 7},
+			new:  []int{2, 3, 7, 8},
+			equal: func(old, new any) bool {
+				return old != 7
+			},
+			want: []DiffEvent[int]{
+				{Kind: Removed, Key: 1, Old: 1},
+				{Kind: Added, Key: 2, New: 2},
+				{Kind: Removed, Key: 5, Old: 5},
+				{Kind: Changed, Key: 7, Old: 7, New: 7},
+				{Kind: Added, Key: 8, New: 8},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			a, b := New[int](), New[int]()
+			for _, key := range tt.old {
+				a.Insert(key, key)
+			}
+			for _, key := range tt.new {
+				b.Insert(key, key)
+			}
+
+			var got []DiffEvent[int]
+			Diff(a, b, tt.equal, func(e DiffEvent[int]) bool {
+				got = append(got, e)
+				return true
+			})
+			if !reflect.DeepEqual(got, tt.want) {
+				t1.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff_stop(t1 *testing.T) {
+	a, b := New[int](), New[int]()
+	for i := 0; i < 10; i++ {
+		b.Insert(i, []int{i})
+	}
+
+	count := 0
+	Diff(a, b, nil, func(e DiffEvent[int]) bool {
+		count++
+		return count < 3
+	})
+	if count != 3 {
+		t1.Errorf("Diff() called fn %v times after stop, want 3", count)
+	}
+}
+
+func TestDiffSnapshots(t1 *testing.T) {
+	t := NewSnapshotTree[int]()
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		t.Insert(rng.Intn(2000), i)
+	}
+
+	for round := 0; round < 50; round++ {
+		old := t.Snapshot()
+		for i := rng.Intn(5); i >= 0; i-- {
+			key := rng.Intn(2000)
+			if rng.Intn(3) == 0 {
+				t.Delete(key)
+			} else {
+				t.Insert(key, rng.Intn(3))
+			}
+		}
+		current := t.Snapshot()
+
+		compared := 0
+		var got []DiffEvent[int]
+		DiffSnapshots(old, current, func(old, new any) bool {
+			compared++
+			return old == new
+		}, func(e DiffEvent[int]) bool {
+			got = append(got, e)
+			return true
+		})
+
+		var want []DiffEvent[int]
+		Diff(treeOf(old), treeOf(current), nil, func(e DiffEvent[int]) bool {
+			want = append(want, e)
+			return true
+		})
+		if !reflect.DeepEqual(got, want) {
+			t1.Fatalf("DiffSnapshots() = %v, want %v", got, want)
+		}
+		if compared > 200 {
+			t1.Errorf("DiffSnapshots() compared %v values, shared subtrees are not skipped", compared)
+		}
+	}
+}
+
+// treeOf copies elements of snapshot to Tree
+func treeOf(s *Snapshot[int]) *Tree[int] {
+	t := New[int]()
+	s.Ascend(func(key int, value any) bool {
+		t.Insert(key, value)
+		return true
+	})
+
+	return t
+}
@@ -0,0 +1,389 @@
+package rbtree
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/exp/constraints"
+)
+
+const (
+	walFileName          = "wal.log"
+	snapshotFileName     = "snapshot"
+	snapshotTempFileName = "snapshot.tmp"
+)
+
+// WAL record's operations, replication stream uses the same operations
+const (
+	walInsert byte = iota + 1
+	walDelete
+)
+
+// walHeaderLen is length of WAL record's header: length and CRC32 of payload
+const walHeaderLen = 8
+
+// walMaxRecordLen is max length of WAL record's payload.
+// Longer length in header means corrupted record, so replay never allocates more
+const walMaxRecordLen = 64 << 20
+
+// SyncPolicy defines when DurableTree calls fsync for write-ahead log
+type SyncPolicy int
+
+const (
+	// SyncAlways - fsync after every change, change is durable when method returns
+	SyncAlways SyncPolicy = iota
+	// SyncInterval - fsync after change if previous fsync was more than DurableOptions.SyncInterval ago
+	SyncInterval
+	// SyncNever - fsync is never called for write-ahead log, OS decides when data is written to disk
+	SyncNever
+)
+
+// DurableOptions is the structure of DurableTree's settings
+type DurableOptions struct {
+	// Sync is policy of fsync calls for write-ahead log
+	Sync SyncPolicy
+	// SyncInterval is min interval between fsync calls for SyncInterval policy
+	SyncInterval time.Duration
+	// SnapshotEvery is count of changes in write-ahead log after which snapshot is written, 0 - never
+	SnapshotEvery int
+	// Values is codec of values, GobValueCodec is used if it's nil
+	Values ValueCodec
+}
+
+// DurableTree is a tree which is stored in directory on disk.
+// Every change is appended to write-ahead log before it's applied to tree,
+// snapshot of tree replaces log from time to time.
+// On open snapshot is loaded and log is replayed, corrupted tail of log is truncated.
+// If log can't be restored after failed write, all next changes return error, tree should be reopened.
+// DurableTree is not safe for concurrent use
+type DurableTree[V constraints.Ordered] struct {
+	tree     *Tree[V]
+	dir      string
+	opts     DurableOptions
+	codecs   *codecs[V]
+	wal      *os.File
+	walSize  int64
+	walOps   int
+	lastSync time.Time
+	buf      []byte
+	failed   error
+}
+
+// OpenDurableTree is a function for opening tree stored in directory dir (directory is created if it doesn't exist)
+// - param dir is path of tree's directory
+// - param opts are settings of tree
+func OpenDurableTree[V constraints.Ordered](dir string, opts DurableOptions) (*DurableTree[V], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	t := &DurableTree[V]{
+		tree: New[V](),
+		dir:  dir,
+		opts: opts,
+	}
+	t.tree.SetCodecs(nil, opts.Values)
+	t.codecs = t.tree.codecs
+
+	if err := t.loadSnapshot(); err != nil {
+		return nil, err
+	}
+	if err := t.replayWAL(); err != nil {
+		if t.wal != nil {
+			t.wal.Close()
+		}
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Tree is a function for getting tree for reading. Tree must not be changed directly.
+func (t *DurableTree[V]) Tree() *Tree[V] {
+	return t.tree
+}
+
+// Insert is a function for inserting element into tree.
+// If element with the same key exists, its value is replaced.
+// - param key should be `ordered type` (`int`, `string`, `float` etc.)
+// - param value can be any type
+func (t *DurableTree[V]) Insert(key V, value any) error {
+	if t.failed != nil {
+		return t.failed
+	}
+
+	b, err := appendOp(t.buf[:0], t.codecs, walInsert, key, value)
+	if err != nil {
+		return err
+	}
+	if err := t.appendWAL(b); err != nil {
+		return err
+	}
+	t.tree.Insert(key, value)
+
+	return t.afterChange()
+}
+
+// Delete is a function for deleting element from tree
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *DurableTree[V]) Delete(key V) error {
+	if t.failed != nil {
+		return t.failed
+	}
+	if !t.tree.Exists(key) {
+		return nil
+	}
+
+	b, err := appendOp(t.buf[:0], t.codecs, walDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	if err := t.appendWAL(b); err != nil {
+		return err
+	}
+	t.tree.Delete(key)
+
+	return t.afterChange()
+}
+
+// Snapshot is a function for writing snapshot of tree and clearing write-ahead log
+func (t *DurableTree[V]) Snapshot() error {
+	if t.failed != nil {
+		return t.failed
+	}
+
+	tmp := filepath.Join(t.dir, snapshotTempFileName)
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	if _, err = t.tree.WriteTo(w); err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Rename(tmp, filepath.Join(t.dir, snapshotFileName)); err != nil {
+		return err
+	}
+	if err := syncDir(t.dir); err != nil {
+		return err
+	}
+
+	// log is replayed over snapshot if process stops here: changes of log are already in snapshot,
+	// replaying them again gives the same tree
+	if err := t.wal.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := t.wal.Seek(0, io.SeekStart); err != nil {
+		return t.fail(err)
+	}
+	t.walSize = 0
+	t.walOps = 0
+
+	return t.wal.Sync()
+}
+
+// Close is a function for syncing and closing write-ahead log
+func (t *DurableTree[V]) Close() error {
+	err := t.wal.Sync()
+	if closeErr := t.wal.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// loadSnapshot - internal function for loading tree from snapshot file if it exists
+func (t *DurableTree[V]) loadSnapshot() error {
+	f, err := os.Open(filepath.Join(t.dir, snapshotFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = t.tree.ReadFrom(bufio.NewReader(f))
+
+	return err
+}
+
+// replayWAL - internal function for applying write-ahead log to tree and truncating its corrupted tail
+func (t *DurableTree[V]) replayWAL() error {
+	f, err := os.OpenFile(filepath.Join(t.dir, walFileName), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	t.wal = f
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	r := bufio.NewReader(f)
+	header := make([]byte, walHeaderLen)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			break
+		}
+		// length is checked before allocation: it can't be more than the rest of file
+		length := int64(binary.BigEndian.Uint32(header))
+		if length > walMaxRecordLen || length > info.Size()-t.walSize-walHeaderLen {
+			break
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			break
+		}
+		if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:]) {
+			break
+		}
+		if err := applyOp(t.tree, t.codecs, payload); err != nil {
+			break
+		}
+		t.walSize += int64(walHeaderLen + len(payload))
+		t.walOps++
+	}
+
+	// everything after the last correct record is a corrupted tail
+	if err := f.Truncate(t.walSize); err != nil {
+		return err
+	}
+	_, err = f.Seek(t.walSize, io.SeekStart)
+
+	return err
+}
+
+// appendWAL - internal function for writing record with payload to write-ahead log
+func (t *DurableTree[V]) appendWAL(payload []byte) error {
+	if len(payload) > walMaxRecordLen {
+		return errors.New(fmt.Sprintf("WAL record of %v bytes is longer than %v bytes", len(payload), walMaxRecordLen))
+	}
+
+	record := make([]byte, walHeaderLen, walHeaderLen+len(payload))
+	binary.BigEndian.PutUint32(record, uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:], crc32.ChecksumIEEE(payload))
+	record = append(record, payload...)
+	t.buf = payload
+
+	if _, err := t.wal.Write(record); err != nil {
+		// remove partially written record, so next records are not lost behind it on replay
+		if truncErr := t.wal.Truncate(t.walSize); truncErr != nil {
+			return t.fail(truncErr)
+		}
+		if _, seekErr := t.wal.Seek(t.walSize, io.SeekStart); seekErr != nil {
+			return t.fail(seekErr)
+		}
+		return err
+	}
+	t.walSize += int64(len(record))
+	t.walOps++
+
+	switch t.opts.Sync {
+	case SyncAlways:
+		return t.wal.Sync()
+	case SyncInterval:
+		if time.Since(t.lastSync) >= t.opts.SyncInterval {
+			t.lastSync = time.Now()
+			return t.wal.Sync()
+		}
+	}
+
+	return nil
+}
+
+// fail - internal function for marking tree as failed when write-ahead log can't be restored,
+// next records could be lost behind broken record on replay
+func (t *DurableTree[V]) fail(err error) error {
+	t.failed = errors.New(fmt.Sprintf("write-ahead log is broken, tree should be reopened: %v", err))
+
+	return t.failed
+}
+
+// afterChange - internal function for writing snapshot when log is long enough
+func (t *DurableTree[V]) afterChange() error {
+	if t.opts.SnapshotEvery > 0 && t.walOps >= t.opts.SnapshotEvery {
+		return t.Snapshot()
+	}
+
+	return nil
+}
+
+// syncDir - internal function for syncing directory's entries (renamed files)
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
+
+// appendOp - internal function for encoding operation with element (or only key for delete) to payload
+// of WAL record or replication frame
+func appendOp[V constraints.Ordered](b []byte, c *codecs[V], op byte, key V, value any) ([]byte, error) {
+	if op == walInsert {
+		return appendElement(append(b, op), c, key, value)
+	}
+
+	kb, err := c.keys.AppendKey(nil, key)
+	if err != nil {
+		return b, err
+	}
+	b = binary.AppendUvarint(append(b, op), uint64(len(kb)))
+
+	return append(b, kb...), nil
+}
+
+// applyOp - internal function for applying operation encoded by appendOp to tree
+func applyOp[V constraints.Ordered](t *Tree[V], c *codecs[V], payload []byte) error {
+	if len(payload) == 0 {
+		return errors.New("empty operation")
+	}
+
+	r := &checksumReader{r: bytes.NewReader(payload[1:]), crc: crc32.NewIEEE()}
+	switch payload[0] {
+	case walInsert:
+		e, err := readElement(r, c)
+		if err != nil {
+			return err
+		}
+		t.Insert(e.key, e.value)
+		return nil
+	case walDelete:
+		kb, err := r.readBytes()
+		if err != nil {
+			return err
+		}
+		key, err := c.keys.DecodeKey(kb)
+		if err != nil {
+			return err
+		}
+		t.Delete(key)
+		return nil
+	}
+
+	return errors.New(fmt.Sprintf("unknown operation %v", payload[0]))
+}
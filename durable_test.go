@@ -0,0 +1,201 @@
+package rbtree
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestDurableTree_Reopen(t1 *testing.T) {
+	tests := []struct {
+		name string
+		opts DurableOptions
+	}{
+		{name: "sync always", opts: DurableOptions{Sync: SyncAlways}},
+		{name: "sync never with snapshots", opts: DurableOptions{Sync: SyncNever, SnapshotEvery: 7}},
+		{name: "sync interval", opts: DurableOptions{Sync: SyncInterval, SnapshotEvery: 100}},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			dir := t1.TempDir()
+			t, err := OpenDurableTree[int](dir, tt.opts)
+			if err != nil {
+				t1.Fatalf("OpenDurableTree() error = %v", err)
+			}
+			want := New[int]()
+			for i := 0; i < 50; i++ {
+				mustNoErr(t1, t.Insert(i%20, i))
+				want.Insert(i%20, i)
+				if i%3 == 0 {
+					mustNoErr(t1, t.Delete(i%7))
+					want.Delete(i % 7)
+				}
+			}
+			mustNoErr(t1, t.Close())
+
+			reopened, err := OpenDurableTree[int](dir, tt.opts)
+			if err != nil {
+				t1.Fatalf("OpenDurableTree() error = %v", err)
+			}
+			defer reopened.Close()
+			checkTreeProperties(t1, reopened.Tree())
+			if !reflect.DeepEqual(reopened.Tree().elements(), want.elements()) {
+				t1.Errorf("reopened tree = %v, want %v", reopened.Tree().elements(), want.elements())
+			}
+		})
+	}
+}
+
+func TestDurableTree_CorruptedTail(t1 *testing.T) {
+	dir := t1.TempDir()
+	t, err := OpenDurableTree[string](dir, DurableOptions{})
+	if err != nil {
+		t1.Fatalf("OpenDurableTree() error = %v", err)
+	}
+	mustNoErr(t1, t.Insert("a", 1))
+	mustNoErr(t1, t.Insert("b", 2))
+	mustNoErr(t1, t.Close())
+
+	wal := filepath.Join(dir, walFileName)
+	info, _ := os.Stat(wal)
+	goodSize := info.Size()
+
+	// torn record: header promises more bytes than written
+	f, _ := os.OpenFile(wal, os.O_APPEND|os.O_WRONLY, 0o644)
+	f.Write([]byte{0, 0, 0, 100, 1, 2, 3, 4, walInsert, 1})
+	f.Close()
+
+	t, err = OpenDurableTree[string](dir, DurableOptions{})
+	if err != nil {
+		t1.Fatalf("OpenDurableTree() error = %v", err)
+	}
+	if info, _ := os.Stat(wal); info.Size() != goodSize {
+		t1.Errorf("corrupted tail wasn't truncated, size %v, want %v", info.Size(), goodSize)
+	}
+	mustNoErr(t1, t.Insert("c", 3))
+	mustNoErr(t1, t.Close())
+
+	// record with wrong checksum
+	data, _ := os.ReadFile(wal)
+	data[len(data)-1] ^= 0xff
+	os.WriteFile(wal, data, 0o644)
+
+	t, err = OpenDurableTree[string](dir, DurableOptions{})
+	if err != nil {
+		t1.Fatalf("OpenDurableTree() error = %v", err)
+	}
+	defer t.Close()
+	want := []element[string]{{key: "a", value: 1}, {key: "b", value: 2}}
+	if !reflect.DeepEqual(t.Tree().elements(), want) {
+		t1.Errorf("tree = %v, want %v", t.Tree().elements(), want)
+	}
+}
+
+func TestDurableTree_HugeRecordLength(t1 *testing.T) {
+	dir := t1.TempDir()
+	t, err := OpenDurableTree[string](dir, DurableOptions{})
+	if err != nil {
+		t1.Fatalf("OpenDurableTree() error = %v", err)
+	}
+	mustNoErr(t1, t.Insert("a", 1))
+	mustNoErr(t1, t.Close())
+
+	wal := filepath.Join(dir, walFileName)
+	info, _ := os.Stat(wal)
+	goodSize := info.Size()
+
+	// garbage header with length of about 4 GiB
+	f, _ := os.OpenFile(wal, os.O_APPEND|os.O_WRONLY, 0o644)
+	f.Write([]byte{0xff, 0xff, 0xff, 0xf0, 1, 2, 3, 4, 9, 9})
+	f.Close()
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	t, err = OpenDurableTree[string](dir, DurableOptions{})
+	runtime.ReadMemStats(&after)
+	if err != nil {
+		t1.Fatalf("OpenDurableTree() error = %v", err)
+	}
+	defer t.Close()
+
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > walMaxRecordLen {
+		t1.Errorf("replay allocated %v bytes", allocated)
+	}
+	if info, _ := os.Stat(wal); info.Size() != goodSize {
+		t1.Errorf("corrupted tail wasn't truncated, size %v, want %v", info.Size(), goodSize)
+	}
+	if value, err := t.Tree().GetValue("a"); err != nil || value != 1 {
+		t1.Errorf("GetValue() = %v, %v, want 1", value, err)
+	}
+}
+
+func TestDurableTree_SnapshotWithStaleLog(t1 *testing.T) {
+	dir := t1.TempDir()
+	t, err := OpenDurableTree[int](dir, DurableOptions{})
+	if err != nil {
+		t1.Fatalf("OpenDurableTree() error = %v", err)
+	}
+	mustNoErr(t1, t.Insert(1, 1))
+	mustNoErr(t1, t.Insert(2, 2))
+	mustNoErr(t1, t.Delete(1))
+	wal, _ := os.ReadFile(filepath.Join(dir, walFileName))
+	mustNoErr(t1, t.Snapshot())
+	mustNoErr(t1, t.Close())
+
+	// process stopped after snapshot was written, but before log was cleared
+	os.WriteFile(filepath.Join(dir, walFileName), wal, 0o644)
+
+	t, err = OpenDurableTree[int](dir, DurableOptions{})
+	if err != nil {
+		t1.Fatalf("OpenDurableTree() error = %v", err)
+	}
+	defer t.Close()
+	if want := []element[int]{{key: 2, value: 2}}; !reflect.DeepEqual(t.Tree().elements(), want) {
+		t1.Errorf("tree = %v, want %v", t.Tree().elements(), want)
+	}
+}
+
+func mustNoErr(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDurableTree_BrokenWAL(t1 *testing.T) {
+	dir := t1.TempDir()
+	t, err := OpenDurableTree[string](dir, DurableOptions{})
+	if err != nil {
+		t1.Fatalf("OpenDurableTree() error = %v", err)
+	}
+	mustNoErr(t1, t.Insert("a", 1))
+
+	// read-only file can be neither written nor truncated
+	wal := t.wal
+	readOnly, err := os.Open(filepath.Join(dir, walFileName))
+	if err != nil {
+		t1.Fatalf("Open() error = %v", err)
+	}
+	t.wal = readOnly
+	if err := t.Insert("b", 2); err == nil {
+		t1.Fatalf("Insert() into read-only WAL without error")
+	}
+	readOnly.Close()
+
+	t.wal = wal
+	if err := t.Insert("c", 3); err == nil {
+		t1.Errorf("Insert() after broken WAL without error")
+	}
+	if err := t.Delete("a"); err == nil {
+		t1.Errorf("Delete() after broken WAL without error")
+	}
+	if err := t.Snapshot(); err == nil {
+		t1.Errorf("Snapshot() after broken WAL without error")
+	}
+	if want := []element[string]{{key: "a", value: 1}}; !reflect.DeepEqual(t.Tree().elements(), want) {
+		t1.Errorf("tree after broken WAL = %v, want %v", t.Tree().elements(), want)
+	}
+	mustNoErr(t1, t.Close())
+}
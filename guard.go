@@ -0,0 +1,64 @@
+package rbtree
+
+import "sync/atomic"
+
+// guard is the structure for detecting concurrent misuse of tree.
+// It counts active writers and readers of tree, like Go maps do,
+// the check is best-effort: it catches only overlapping calls.
+// Writes to frozen tree always panic
+type guard struct {
+	enabled bool
+	frozen  bool
+	writers int32
+	readers int32
+}
+
+func (g *guard) active() bool {
+	return g.enabled || checkAccessByDefault
+}
+
+func (g *guard) startWrite() {
+	if g.frozen {
+		panic("rbtree: write to frozen tree")
+	}
+	if !g.active() {
+		return
+	}
+
+	if atomic.AddInt32(&g.writers, 1) != 1 {
+		atomic.AddInt32(&g.writers, -1)
+		panic("rbtree: concurrent tree writes")
+	}
+	if atomic.LoadInt32(&g.readers) != 0 {
+		atomic.AddInt32(&g.writers, -1)
+		panic("rbtree: concurrent tree read and tree write")
+	}
+}
+
+func (g *guard) endWrite() {
+	if !g.active() {
+		return
+	}
+
+	atomic.AddInt32(&g.writers, -1)
+}
+
+func (g *guard) startRead() {
+	if !g.active() {
+		return
+	}
+
+	atomic.AddInt32(&g.readers, 1)
+	if atomic.LoadInt32(&g.writers) != 0 {
+		atomic.AddInt32(&g.readers, -1)
+		panic("rbtree: concurrent tree read and tree write")
+	}
+}
+
+func (g *guard) endRead() {
+	if !g.active() {
+		return
+	}
+
+	atomic.AddInt32(&g.readers, -1)
+}
@@ -0,0 +1,6 @@
+//go:build rbtreecheck
+
+package rbtree
+
+// checkAccessByDefault enables guard for all trees
+const checkAccessByDefault = true
@@ -0,0 +1,109 @@
+package rbtree
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTree_AccessCheck(t1 *testing.T) {
+	type testCase struct {
+		name    string
+		enabled bool
+		misuse  func(t *Tree[int])
+		wantErr string
+	}
+
+	tests := []testCase{
+		{
+			name:    "write during iteration",
+			enabled: true,
+			misuse: func(t *Tree[int]) {
+				t.Ascend(func(key int, value any) bool {
+					t.Delete(key)
+					return true
+				})
+			},
+			wantErr: "concurrent tree read and tree write",
+		},
+		{
+			name:    "overlapping writers",
+			enabled: true,
+			misuse: func(t *Tree[int]) {
+				t.guard.startWrite()
+				defer t.guard.endWrite()
+				t.Insert(1, 1)
+			},
+			wantErr: "concurrent tree writes",
+		},
+		{
+			name:    "read during write",
+			enabled: true,
+			misuse: func(t *Tree[int]) {
+				t.guard.startWrite()
+				defer t.guard.endWrite()
+				t.Exists(1)
+			},
+			wantErr: "concurrent tree read and tree write",
+		},
+		{
+			name:    "write to split tree during iteration",
+			enabled: true,
+			misuse: func(t *Tree[int]) {
+				right := t.Split(2)
+				right.Ascend(func(key int, value any) bool {
+					right.Delete(key)
+					return true
+				})
+			},
+			wantErr: "concurrent tree read and tree write",
+		},
+		{
+			name:    "reads during iteration",
+			enabled: true,
+			misuse: func(t *Tree[int]) {
+				t.Ascend(func(key int, value any) bool {
+					return t.Exists(key)
+				})
+			},
+		},
+		{
+			name:    "check is disabled",
+			enabled: false,
+			misuse: func(t *Tree[int]) {
+				t.guard.startWrite()
+				defer t.guard.endWrite()
+				t.Insert(1, 1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			if !tt.enabled && checkAccessByDefault {
+				t1.Skip("check is enabled by build tag")
+			}
+
+			var t *Tree[int]
+			if tt.enabled {
+				t = New[int](WithAccessCheck())
+			} else {
+				t = New[int]()
+			}
+			t.Insert(1, 1)
+			t.Insert(2, 2)
+
+			var err string
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						err, _ = r.(string)
+					}
+				}()
+				tt.misuse(t)
+			}()
+
+			if tt.wantErr == "" && err != "" || !strings.Contains(err, tt.wantErr) {
+				t1.Errorf("panic = %q, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,334 @@
+package rbtree
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+// hybridChunks is count of key ranges which memory is divided into when the coldest range is searched
+const hybridChunks = 16
+
+// HybridTree is a tree which keeps within memory budget by spilling cold key ranges to disk.
+// Range is cold if all its keys were accessed earlier than keys of other ranges.
+// Spilled ranges are sorted string tables in directory, they are paged back in memory
+// when any key of range is read or changed. Range iterates over memory and disk without paging in.
+// HybridTree is not safe for concurrent use
+type HybridTree[V constraints.Ordered] struct {
+	dir      string
+	budget   int
+	values   ValueCodec
+	mem      *Tree[V] // values are *hybridEntry
+	segments []*segment[V]
+	clock    uint64
+}
+
+// hybridEntry is the value of HybridTree's element in memory
+type hybridEntry struct {
+	value  any
+	access uint64
+}
+
+// Size is a function for estimation of entry's memory in bytes
+func (e *hybridEntry) Size() int {
+	return valueBytes(e.value) + 16
+}
+
+// segment is the structure of key range [lo, hi] spilled to disk. Memory never has keys of this range
+type segment[V constraints.Ordered] struct {
+	lo, hi V
+	path   string
+	file   *os.File
+	reader *SSTableReader[V]
+}
+
+// NewHybridTree is a function for creation empty tree which spills cold ranges to directory
+// - param dir is directory for spilled ranges, it's created if it doesn't exist
+// - param budget is max estimated size of memory in bytes (see Tree.Bytes)
+// - param values is codec of values, GobValueCodec is used if it's nil
+func NewHybridTree[V constraints.Ordered](dir string, budget int, values ValueCodec) (*HybridTree[V], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &HybridTree[V]{
+		dir:    dir,
+		budget: budget,
+		values: values,
+		mem:    New[V](),
+	}, nil
+}
+
+// Insert is a function for inserting element into tree.
+// If element with the same key exists, its value is replaced.
+// - param key should be `ordered type` (`int`, `string`, `float` etc.)
+// - param value can be any type
+func (t *HybridTree[V]) Insert(key V, value any) error {
+	if err := t.pageIn(key); err != nil {
+		return err
+	}
+	t.clock++
+	t.mem.Insert(key, &hybridEntry{value: value, access: t.clock})
+
+	return t.evict()
+}
+
+// Delete is a function for deleting element from tree
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *HybridTree[V]) Delete(key V) error {
+	if err := t.pageIn(key); err != nil {
+		return err
+	}
+	t.mem.Delete(key)
+
+	return t.evict()
+}
+
+// GetValue is a function for searching element in tree and returning value of this element
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *HybridTree[V]) GetValue(key V) (any, error) {
+	if err := t.pageIn(key); err != nil {
+		return nil, err
+	}
+
+	n := t.mem.search(key)
+	if n == nil {
+		return nil, errors.New(fmt.Sprintf("element with key %v not found", key))
+	}
+	t.clock++
+	e := n.element.value.(*hybridEntry)
+	e.access = t.clock
+
+	return e.value, t.evict()
+}
+
+// Len is a function for getting count of elements in memory and on disk.
+func (t *HybridTree[V]) Len() int {
+	result := t.mem.Len()
+	for _, s := range t.segments {
+		result += s.reader.Len()
+	}
+
+	return result
+}
+
+// Bytes is a function for getting estimated size of memory in bytes.
+func (t *HybridTree[V]) Bytes() int {
+	return t.mem.Bytes()
+}
+
+// Range is a function for iterating over elements with keys in range [lo, hi) in key order.
+// Spilled elements are read from disk and are not paged in.
+// Iteration stops when fn returns false.
+// - params lo and hi should be `ordered type` (`int`, `string`, `float` etc)
+func (t *HybridTree[V]) Range(lo, hi V, fn func(key V, value any) bool) error {
+	i := sort.Search(len(t.segments), func(i int) bool { return t.segments[i].hi >= lo })
+	stopped := false
+	var err error
+
+	// segments which are before key k in key order
+	scanBefore := func(k V, all bool) {
+		for ; i < len(t.segments) && !stopped && err == nil; i++ {
+			if t.segments[i].lo >= hi || !all && t.segments[i].hi >= k {
+				return
+			}
+			err = t.segments[i].reader.ScanRange(lo, hi, func(key V, value any) bool {
+				stopped = !fn(key, value)
+				return !stopped
+			})
+		}
+	}
+
+	t.mem.AscendRange(lo, hi, func(key V, value any) bool {
+		scanBefore(key, false)
+		if stopped || err != nil {
+			return false
+		}
+		stopped = !fn(key, value.(*hybridEntry).value)
+		return !stopped
+	})
+	scanBefore(hi, true)
+
+	return err
+}
+
+// Close is a function for closing and removing all spilled files. Tree can't be used after Close.
+func (t *HybridTree[V]) Close() error {
+	var result error
+	for _, s := range t.segments {
+		if err := s.remove(); err != nil && result == nil {
+			result = err
+		}
+	}
+	t.segments = nil
+
+	return result
+}
+
+// pageIn - internal function for loading segment which contains key into memory
+func (t *HybridTree[V]) pageIn(key V) error {
+	i := sort.Search(len(t.segments), func(i int) bool { return t.segments[i].hi >= key })
+	if i == len(t.segments) || key < t.segments[i].lo {
+		return nil
+	}
+
+	s := t.segments[i]
+	elements, err := s.elements()
+	if err != nil {
+		return err
+	}
+
+	t.clock++
+	for _, e := range elements {
+		t.mem.Insert(e.key, &hybridEntry{value: e.value, access: t.clock})
+	}
+	t.segments = append(t.segments[:i], t.segments[i+1:]...)
+	// elements are already in memory, file which can't be removed is only garbage
+	s.remove()
+
+	return nil
+}
+
+// evict - internal function for spilling the coldest ranges to disk while memory is over budget
+func (t *HybridTree[V]) evict() error {
+	for t.mem.Bytes() > t.budget && t.mem.Len() > 0 {
+		first, last := t.coldestRange()
+		if err := t.spill(first, last); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// coldestRange - internal function for searching range of memory keys with the oldest last access
+func (t *HybridTree[V]) coldestRange() (V, V) {
+	chunk := t.mem.Len()/hybridChunks + 1
+	var first, last, coldestFirst, coldestLast V
+	var lastAccess, coldest uint64
+	i := 0
+
+	t.mem.Ascend(func(key V, value any) bool {
+		if i%chunk == 0 {
+			first, lastAccess = key, 0
+		}
+		if access := value.(*hybridEntry).access; access > lastAccess {
+			lastAccess = access
+		}
+		last = key
+		i++
+
+		if i%chunk == 0 || i == t.mem.Len() {
+			if coldest == 0 || lastAccess < coldest {
+				coldestFirst, coldestLast, coldest = first, last, lastAccess
+			}
+		}
+		return true
+	})
+
+	return coldestFirst, coldestLast
+}
+
+// spill - internal function for writing memory keys in range [first, last] to disk
+// together with segments inside this range
+func (t *HybridTree[V]) spill(first, last V) error {
+	var elements []element[V]
+	var merged []*segment[V]
+	var kept []*segment[V]
+
+	for _, s := range t.segments {
+		if s.lo < first || s.hi > last {
+			kept = append(kept, s)
+			continue
+		}
+		merged = append(merged, s)
+	}
+
+	tree := New[V]()
+	tree.SetCodecs(nil, t.values)
+	t.mem.ascendFrom(t.mem.ceiling(first, false), func(key V, value any) bool {
+		if key > last {
+			return false
+		}
+		elements = append(elements, element[V]{key: key, value: value.(*hybridEntry).value})
+		return true
+	})
+	for _, s := range merged {
+		spilled, err := s.elements()
+		if err != nil {
+			return err
+		}
+		elements = append(elements, spilled...)
+	}
+	tree.build(sortElements(elements))
+
+	s := &segment[V]{lo: first, hi: last}
+	if err := s.write(t.dir, tree, t.values); err != nil {
+		return err
+	}
+
+	// new segment replaces memory keys and merged segments before any cleanup,
+	// so failed removal of old file can't lose elements
+	t.segments = append(kept, s)
+	sort.Slice(t.segments, func(i, j int) bool { return t.segments[i].lo < t.segments[j].lo })
+	for _, e := range elements {
+		t.mem.Delete(e.key)
+	}
+	for _, m := range merged {
+		m.remove()
+	}
+
+	return nil
+}
+
+// write - internal function for writing tree to new segment's file in dir and opening it for reading.
+// Name of file is unique, so files of other trees in dir are never overwritten
+func (s *segment[V]) write(dir string, tree *Tree[V], values ValueCodec) error {
+	f, err := os.CreateTemp(dir, "segment-*.sst")
+	if err != nil {
+		return err
+	}
+	s.path = f.Name()
+
+	w := bufio.NewWriter(f)
+	size, err := tree.WriteSSTable(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		s.reader, err = OpenSSTable[V](f, size, nil, values)
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(s.path)
+		return err
+	}
+	s.file = f
+
+	return nil
+}
+
+// elements - internal function for reading all elements of segment
+func (s *segment[V]) elements() ([]element[V], error) {
+	elements := make([]element[V], 0, s.reader.Len())
+	err := s.reader.Scan(s.lo, func(key V, value any) bool {
+		elements = append(elements, element[V]{key: key, value: value})
+		return true
+	})
+
+	return elements, err
+}
+
+// remove - internal function for closing and removing segment's file
+func (s *segment[V]) remove() error {
+	err := s.file.Close()
+	if removeErr := os.Remove(s.path); err == nil {
+		err = removeErr
+	}
+
+	return err
+}
@@ -0,0 +1,113 @@
+package rbtree
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestHybridTree(t1 *testing.T) {
+	dir := t1.TempDir()
+	t, err := NewHybridTree[int](dir, 4000, nil)
+	if err != nil {
+		t1.Fatalf("NewHybridTree() error = %v", err)
+	}
+
+	for i := 0; i < 500; i++ {
+		mustNoErr(t1, t.Insert(i, i*10))
+		if t.Bytes() > 4000 {
+			t1.Fatalf("Bytes() = %v is over budget", t.Bytes())
+		}
+	}
+	if len(t.segments) == 0 {
+		t1.Fatalf("nothing was spilled to disk")
+	}
+	if t.Len() != 500 {
+		t1.Errorf("Len() = %v, want 500", t.Len())
+	}
+
+	// the oldest keys are cold
+	if t.mem.Exists(0) || !t.mem.Exists(499) {
+		t1.Errorf("cold keys are in memory, hot keys are on disk")
+	}
+
+	var got, want []any
+	for i := 100; i < 400; i++ {
+		want = append(want, i*10)
+	}
+	err = t.Range(100, 400, func(key int, value any) bool {
+		got = append(got, value)
+		return true
+	})
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t1.Errorf("Range() = %v elements, %v, want %v elements", len(got), err, len(want))
+	}
+
+	mustNoErr(t1, t.Delete(3))
+	for _, key := range []int{0, 250, 499} {
+		if value, err := t.GetValue(key); err != nil || value != key*10 {
+			t1.Errorf("GetValue(%v) = %v, %v, want %v", key, value, err, key*10)
+		}
+	}
+	if !t.mem.Exists(0) {
+		t1.Errorf("accessed key wasn't paged in")
+	}
+	if _, err := t.GetValue(3); err == nil {
+		t1.Errorf("GetValue() of deleted key without error")
+	}
+	if t.Len() != 499 {
+		t1.Errorf("Len() = %v, want 499", t.Len())
+	}
+
+	count, prev := 0, -1
+	t.Range(-1, 1000, func(key int, value any) bool {
+		if key <= prev {
+			t1.Errorf("Range() key %v after %v", key, prev)
+		}
+		prev = key
+		count++
+		return true
+	})
+	if count != 499 {
+		t1.Errorf("Range() visited %v elements, want 499", count)
+	}
+
+	mustNoErr(t1, t.Close())
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t1.Errorf("Close() left %v files", len(files))
+	}
+}
+
+func TestHybridTree_spillFiles(t1 *testing.T) {
+	dir := t1.TempDir()
+	// file of another tree in the same directory
+	other := filepath.Join(dir, "segment-1.sst")
+	mustNoErr(t1, os.WriteFile(other, []byte("data"), 0o644))
+
+	t, err := NewHybridTree[int](dir, 1<<20, nil)
+	if err != nil {
+		t1.Fatalf("NewHybridTree() error = %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		mustNoErr(t1, t.Insert(i, i))
+	}
+	mustNoErr(t1, t.spill(0, 4))
+
+	// file of merged segment can't be removed, elements are kept anyway
+	mustNoErr(t1, os.Remove(t.segments[0].path))
+	mustNoErr(t1, t.spill(0, 9))
+	if len(t.segments) != 1 || t.mem.Len() != 0 || t.Len() != 10 {
+		t1.Fatalf("%v segments and %v keys in memory after spill, Len() = %v", len(t.segments), t.mem.Len(), t.Len())
+	}
+	for i := 0; i < 10; i++ {
+		if value, err := t.GetValue(i); err != nil || value != i {
+			t1.Errorf("GetValue(%v) = %v, %v", i, value, err)
+		}
+	}
+	mustNoErr(t1, t.Close())
+
+	if data, err := os.ReadFile(other); err != nil || string(data) != "data" {
+		t1.Errorf("file of another tree was overwritten: %q, %v", data, err)
+	}
+}
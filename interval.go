@@ -0,0 +1,185 @@
+package rbtree
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Interval is the structure of IntervalTree's element: range [Start, End) with value
+type Interval[V constraints.Ordered] struct {
+	Start V
+	End   V
+	Value any
+}
+
+// IntervalTree is a tree of intervals keyed by start of interval.
+// Every node keeps intervals with the same start sorted by end
+// and the max end of intervals of its subtree, so searches skip subtrees which end before searched range
+type IntervalTree[V constraints.Ordered] struct {
+	tree *AggregateTree[V, maxEnd[V]] // values are []Interval[V]
+	size int
+}
+
+// maxEnd is the aggregate of IntervalTree: the max end of intervals of subtree, ok is false for empty subtree
+type maxEnd[V constraints.Ordered] struct {
+	end V
+	ok  bool
+}
+
+// NewIntervalTree is a function for creation empty interval tree
+// - param should be `ordered type` (`int`, `string`, `float` etc)
+func NewIntervalTree[V constraints.Ordered]() *IntervalTree[V] {
+	return &IntervalTree[V]{
+		tree: NewAggregateTree[V](maxEnd[V]{}, func(left maxEnd[V], key V, value any, right maxEnd[V]) maxEnd[V] {
+			intervals := value.([]Interval[V])
+			result := maxEnd[V]{end: intervals[len(intervals)-1].End, ok: true}
+			for _, m := range []maxEnd[V]{left, right} {
+				if m.ok && m.end > result.end {
+					result.end = m.end
+				}
+			}
+			return result
+		}),
+	}
+}
+
+// Insert is a function for inserting interval [start, end) into tree.
+// If interval with the same start and end exists, its value is replaced.
+// Error is returned if end isn't greater than start.
+// - params start and end should be `ordered type` (`int`, `string`, `float` etc)
+// - param value can be any type
+func (t *IntervalTree[V]) Insert(start, end V, value any) error {
+	if end <= start {
+		return errors.New(fmt.Sprintf("end %v of interval should be greater than start %v", end, start))
+	}
+
+	var intervals []Interval[V]
+	if n := t.tree.search(start); n != nil {
+		intervals = n.element.value.([]Interval[V])
+	}
+	i := sort.Search(len(intervals), func(i int) bool { return intervals[i].End >= end })
+
+	// intervals are copied: node's value is replaced by put, which updates aggregates
+	result := make([]Interval[V], 0, len(intervals)+1)
+	result = append(result, intervals[:i]...)
+	result = append(result, Interval[V]{Start: start, End: end, Value: value})
+	if i < len(intervals) && intervals[i].End == end {
+		i++
+	} else {
+		t.size++
+	}
+	result = append(result, intervals[i:]...)
+	t.tree.Insert(start, result)
+
+	return nil
+}
+
+// Delete is a function for deleting interval [start, end) from tree
+// - params start and end should be `ordered type` (`int`, `string`, `float` etc)
+func (t *IntervalTree[V]) Delete(start, end V) {
+	n := t.tree.search(start)
+	if n == nil {
+		return
+	}
+
+	intervals := n.element.value.([]Interval[V])
+	i := sort.Search(len(intervals), func(i int) bool { return intervals[i].End >= end })
+	if i == len(intervals) || intervals[i].End != end {
+		return
+	}
+
+	t.size--
+	if len(intervals) == 1 {
+		t.tree.Delete(start)
+		return
+	}
+	result := make([]Interval[V], 0, len(intervals)-1)
+	result = append(result, intervals[:i]...)
+	t.tree.Insert(start, append(result, intervals[i+1:]...))
+}
+
+// Len is a function for getting count of intervals in tree.
+func (t *IntervalTree[V]) Len() int {
+	return t.size
+}
+
+// Overlapping is a function for iterating over intervals which intersect range [lo, hi)
+// in order of start (and end for the same start). Search takes O(log n) per found interval.
+// Iteration stops when fn returns false.
+// - params lo and hi should be `ordered type` (`int`, `string`, `float` etc)
+func (t *IntervalTree[V]) Overlapping(lo, hi V, fn func(interval Interval[V]) bool) {
+	t.tree.guard.startRead()
+	defer t.tree.guard.endRead()
+
+	if lo < hi {
+		t.overlapping(t.tree.root, lo, hi, false, fn)
+	}
+}
+
+// Stabbing is a function for iterating over intervals which contain point
+// in order of start (and end for the same start). Search takes O(log n) per found interval.
+// Iteration stops when fn returns false.
+// - param point should be `ordered type` (`int`, `string`, `float` etc)
+func (t *IntervalTree[V]) Stabbing(point V, fn func(interval Interval[V]) bool) {
+	t.tree.guard.startRead()
+	defer t.tree.guard.endRead()
+
+	t.overlapping(t.tree.root, point, point, true, fn)
+}
+
+// AnyOverlap is a function for searching any interval which intersects range [lo, hi) in O(log n).
+// It returns false if there is no such interval.
+// - params lo and hi should be `ordered type` (`int`, `string`, `float` etc)
+func (t *IntervalTree[V]) AnyOverlap(lo, hi V) (Interval[V], bool) {
+	t.tree.guard.startRead()
+	defer t.tree.guard.endRead()
+
+	// if left subtree has interval which ends after lo, but doesn't intersect range,
+	// it starts after hi, so all intervals of right subtree start after hi too
+	n := t.tree.root
+	for n != t.tree.nilNode && lo < hi {
+		intervals := n.element.value.([]Interval[V])
+		if last := intervals[len(intervals)-1]; n.element.key < hi && last.End > lo {
+			return last, true
+		}
+
+		if m := t.tree.aggregator.of(n.left); m.ok && m.end > lo {
+			n = n.left
+			continue
+		}
+		n = n.right
+	}
+
+	return Interval[V]{}, false
+}
+
+// overlapping - internal function for iterating over intervals of subtree n with end > lo and start < hi
+// (start <= hi if closed is true). It returns false if fn stopped iteration.
+func (t *IntervalTree[V]) overlapping(n *node[V], lo, hi V, closed bool, fn func(interval Interval[V]) bool) bool {
+	if n == t.tree.nilNode {
+		return true
+	}
+	if m := t.tree.aggregator.of(n); m.end <= lo {
+		return true
+	}
+
+	if !t.overlapping(n.left, lo, hi, closed, fn) {
+		return false
+	}
+	if n.element.key > hi || n.element.key == hi && !closed {
+		return true
+	}
+
+	intervals := n.element.value.([]Interval[V])
+	i := sort.Search(len(intervals), func(i int) bool { return intervals[i].End > lo })
+	for _, interval := range intervals[i:] {
+		if !fn(interval) {
+			return false
+		}
+	}
+
+	return t.overlapping(n.right, lo, hi, closed, fn)
+}
@@ -0,0 +1,99 @@
+package rbtree
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestIntervalTree_Overlapping(t1 *testing.T) {
+	t := NewIntervalTree[int]()
+	want := map[[2]int]int{}
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 3000; i++ {
+		start := rng.Intn(500)
+		end := start + 1 + rng.Intn(50)
+		if rng.Intn(3) == 0 {
+			t.Delete(start, end)
+			delete(want, [2]int{start, end})
+		} else {
+			mustNoErr(t1, t.Insert(start, end, i))
+			want[[2]int{start, end}] = i
+		}
+		if t.Len() != len(want) {
+			t1.Fatalf("Len() = %v, want %v", t.Len(), len(want))
+		}
+
+		lo := rng.Intn(560) - 10
+		hi := lo + rng.Intn(20)
+		got := map[[2]int]int{}
+		prev := Interval[int]{Start: -1}
+		t.Overlapping(lo, hi, func(interval Interval[int]) bool {
+			if interval.Start < prev.Start || interval.Start == prev.Start && interval.End <= prev.End {
+				t1.Fatalf("Overlapping() interval %v after %v", interval, prev)
+			}
+			prev = interval
+			got[[2]int{interval.Start, interval.End}] = interval.Value.(int)
+			return true
+		})
+
+		wantOverlapping := map[[2]int]int{}
+		for interval, value := range want {
+			if lo < hi && interval[0] < hi && interval[1] > lo {
+				wantOverlapping[interval] = value
+			}
+		}
+		if !reflect.DeepEqual(got, wantOverlapping) {
+			t1.Fatalf("Overlapping(%v, %v) = %v, want %v", lo, hi, got, wantOverlapping)
+		}
+
+		interval, found := t.AnyOverlap(lo, hi)
+		if found != (len(wantOverlapping) > 0) {
+			t1.Fatalf("AnyOverlap(%v, %v) found = %v, want %v", lo, hi, found, len(wantOverlapping) > 0)
+		}
+		if _, ok := wantOverlapping[[2]int{interval.Start, interval.End}]; found && !ok {
+			t1.Fatalf("AnyOverlap(%v, %v) = %v, it doesn't intersect range", lo, hi, interval)
+		}
+	}
+}
+
+func TestIntervalTree_Stabbing(t1 *testing.T) {
+	t := NewIntervalTree[int]()
+	mustNoErr(t1, t.Insert(1, 5, "a"))
+	mustNoErr(t1, t.Insert(3, 4, "b"))
+	mustNoErr(t1, t.Insert(3, 8, "c"))
+	mustNoErr(t1, t.Insert(5, 6, "d"))
+	mustNoErr(t1, t.Insert(3, 8, "e"))
+	if err := t.Insert(7, 7, "empty"); err == nil {
+		t1.Errorf("Insert() of empty interval error = nil")
+	}
+
+	tests := []struct {
+		name  string
+		point int
+		want  []any
+	}{
+		{name: "before intervals", point: 0, want: nil},
+		{name: "start of interval", point: 1, want: []any{"a"}},
+		{name: "nested intervals", point: 3, want: []any{"a", "b", "e"}},
+		{name: "end is excluded", point: 5, want: []any{"e", "d"}},
+		{name: "after intervals", point: 8, want: nil},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			var got []any
+			t.Stabbing(tt.point, func(interval Interval[int]) bool {
+				got = append(got, interval.Value)
+				return true
+			})
+			if !reflect.DeepEqual(got, tt.want) {
+				t1.Errorf("Stabbing(%v) = %v, want %v", tt.point, got, tt.want)
+			}
+		})
+	}
+
+	if _, found := t.AnyOverlap(4, 4); found {
+		t1.Errorf("AnyOverlap() of empty range found interval")
+	}
+}
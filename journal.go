@@ -0,0 +1,179 @@
+package rbtree
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Journal is a wrapper of Tree which records every change of tree
+// with enough information to undo and redo it.
+// All changes of tree should be made through Journal
+type Journal[V constraints.Ordered] struct {
+	tree  *Tree[V]
+	undo  []change[V]
+	redo  []change[V]
+	seq   uint64
+	marks map[string]Mark // named savepoints
+}
+
+// change is the structure of one recorded change of element
+type change[V constraints.Ordered] struct {
+	seq     uint64
+	key     V
+	old     any
+	hadOld  bool
+	new     any
+	deleted bool
+}
+
+// Mark is a savepoint of Journal's history
+type Mark struct {
+	pos int
+	seq uint64
+}
+
+// NewJournal is a function for creation journal of tree's changes
+func NewJournal[V constraints.Ordered](t *Tree[V]) *Journal[V] {
+	return &Journal[V]{
+		tree:  t,
+		marks: map[string]Mark{},
+	}
+}
+
+// Tree is a function for getting journaled tree for reading
+func (j *Journal[V]) Tree() *Tree[V] {
+	return j.tree
+}
+
+// Insert is a function for inserting element into tree and recording this change.
+// If element with the same key exists, its value is replaced.
+// - param key should be `ordered type` (`int`, `string`, `float` etc.)
+// - param value can be any type
+func (j *Journal[V]) Insert(key V, value any) {
+	j.tree.guard.startWrite()
+	defer j.tree.guard.endWrite()
+
+	old, hadOld := j.tree.put(key, value)
+	j.record(change[V]{key: key, old: old, hadOld: hadOld, new: value})
+}
+
+// Delete is a function for deleting element from tree and recording this change
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (j *Journal[V]) Delete(key V) {
+	j.tree.guard.startWrite()
+	defer j.tree.guard.endWrite()
+
+	if old, hadOld := j.tree.remove(key); hadOld {
+		j.record(change[V]{key: key, old: old, hadOld: true, deleted: true})
+	}
+}
+
+// Undo is a function for reverting last change. It returns false if there is nothing to undo.
+func (j *Journal[V]) Undo() bool {
+	if len(j.undo) == 0 {
+		return false
+	}
+
+	c := j.undo[len(j.undo)-1]
+	j.undo = j.undo[:len(j.undo)-1]
+	j.apply(c.key, c.old, !c.hadOld)
+	j.redo = append(j.redo, c)
+
+	return true
+}
+
+// Redo is a function for applying last undone change again. It returns false if there is nothing to redo.
+// Redo history is cleared by any new change.
+func (j *Journal[V]) Redo() bool {
+	if len(j.redo) == 0 {
+		return false
+	}
+
+	c := j.redo[len(j.redo)-1]
+	j.redo = j.redo[:len(j.redo)-1]
+	j.apply(c.key, c.new, c.deleted)
+	j.undo = append(j.undo, c)
+
+	return true
+}
+
+// Mark is a function for creation savepoint at current state of tree
+func (j *Journal[V]) Mark() Mark {
+	m := Mark{pos: len(j.undo)}
+	if m.pos > 0 {
+		m.seq = j.undo[m.pos-1].seq
+	}
+
+	return m
+}
+
+// NamedMark is a function for creation savepoint at current state of tree and saving it with name.
+// Savepoint with the same name is replaced.
+func (j *Journal[V]) NamedMark(name string) Mark {
+	m := j.Mark()
+	j.marks[name] = m
+
+	return m
+}
+
+// RollbackTo is a function for returning tree to state of savepoint.
+// Changes made after savepoint are undone and can be applied again by Redo,
+// if savepoint was taken before undone changes, they are redone.
+// It returns error if history of savepoint was discarded by undoing and making new changes.
+func (j *Journal[V]) RollbackTo(m Mark) error {
+	// history is undo stack followed by redo stack in reverse order
+	if m.pos > len(j.undo)+len(j.redo) || m.pos > 0 && j.changeAt(m.pos-1).seq != m.seq {
+		return errors.New("mark is not in journal's history")
+	}
+
+	for len(j.undo) > m.pos {
+		j.Undo()
+	}
+	for len(j.undo) < m.pos {
+		j.Redo()
+	}
+
+	return nil
+}
+
+// RollbackToNamed is a function for returning tree to state of savepoint with name (see RollbackTo).
+// It returns error if there is no savepoint with name.
+func (j *Journal[V]) RollbackToNamed(name string) error {
+	m, ok := j.marks[name]
+	if !ok {
+		return errors.New(fmt.Sprintf("mark %q not found", name))
+	}
+
+	return j.RollbackTo(m)
+}
+
+// changeAt - internal function for getting change at position i of history
+func (j *Journal[V]) changeAt(i int) change[V] {
+	if i < len(j.undo) {
+		return j.undo[i]
+	}
+
+	return j.redo[len(j.redo)-1-(i-len(j.undo))]
+}
+
+// record - internal function for adding change to history
+func (j *Journal[V]) record(c change[V]) {
+	j.seq++
+	c.seq = j.seq
+	j.undo = append(j.undo, c)
+	j.redo = j.redo[:0]
+}
+
+// apply - internal function for setting element's value or deleting element
+func (j *Journal[V]) apply(key V, value any, deleted bool) {
+	j.tree.guard.startWrite()
+	defer j.tree.guard.endWrite()
+
+	if deleted {
+		j.tree.remove(key)
+		return
+	}
+	j.tree.put(key, value)
+}
@@ -0,0 +1,128 @@
+package rbtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJournal_UndoRedo(t1 *testing.T) {
+	j := NewJournal(getTree([]int{22, 8}))
+	j.Insert(4, 4)  // new element
+	j.Insert(8, 80) // value update
+	j.Delete(22)    // deleting
+	j.Delete(100)   // nothing is changed, nothing is recorded
+
+	steps := []*Tree[int]{
+		getTree([]int{22, 8}),
+		getTree([]int{22, 8, 4}),
+		treeWithValue(getTree([]int{22, 8, 4}), 8, 80),
+		treeWithValue(getTree([]int{8, 4}), 8, 80),
+	}
+
+	for i := len(steps) - 2; i >= 0; i-- {
+		if !j.Undo() {
+			t1.Fatalf("Undo() = false at step %v", i)
+		}
+		checkTreeProperties(t1, j.Tree())
+		if !reflect.DeepEqual(j.Tree().elements(), steps[i].elements()) {
+			t1.Errorf("tree after Undo() is different from step %v", i)
+		}
+	}
+	if j.Undo() {
+		t1.Errorf("Undo() of empty history = true")
+	}
+
+	for i := 1; i < len(steps); i++ {
+		if !j.Redo() {
+			t1.Fatalf("Redo() = false at step %v", i)
+		}
+		if !reflect.DeepEqual(j.Tree().elements(), steps[i].elements()) {
+			t1.Errorf("tree after Redo() is different from step %v", i)
+		}
+	}
+	if j.Redo() {
+		t1.Errorf("Redo() of empty history = true")
+	}
+
+	j.Undo()
+	j.Insert(1, 1)
+	if j.Redo() {
+		t1.Errorf("Redo() after new change = true")
+	}
+}
+
+func TestJournal_RollbackTo(t1 *testing.T) {
+	j := NewJournal(New[int]())
+	j.Insert(1, 1)
+	start := j.Mark()
+	j.Insert(2, 2)
+	j.Insert(3, 3)
+	middle := j.Mark()
+	j.Delete(1)
+
+	if err := j.RollbackTo(middle); err != nil {
+		t1.Fatalf("RollbackTo(middle) error = %v", err)
+	}
+	if !reflect.DeepEqual(j.Tree().elements(), getTree([]int{1, 2, 3}).elements()) {
+		t1.Errorf("tree after RollbackTo(middle) is wrong")
+	}
+	if err := j.RollbackTo(start); err != nil {
+		t1.Fatalf("RollbackTo(start) error = %v", err)
+	}
+	if !reflect.DeepEqual(j.Tree().elements(), getTree([]int{1}).elements()) {
+		t1.Errorf("tree after RollbackTo(start) is wrong")
+	}
+
+	// mark taken before undo is reached by redo
+	if err := j.RollbackTo(middle); err != nil {
+		t1.Fatalf("RollbackTo(middle) after undo error = %v", err)
+	}
+	if !reflect.DeepEqual(j.Tree().elements(), getTree([]int{1, 2, 3}).elements()) {
+		t1.Errorf("tree after RollbackTo(middle) forward is wrong")
+	}
+	if !j.Redo() || j.Tree().Exists(1) {
+		t1.Errorf("Redo() after RollbackTo(middle) didn't delete 1 again")
+	}
+	mustNoErr(t1, j.RollbackTo(start))
+
+	// history of middle mark is replaced by new change
+	j.Insert(5, 5)
+	if err := j.RollbackTo(middle); err == nil {
+		t1.Errorf("RollbackTo() of discarded mark without error")
+	}
+}
+
+func TestJournal_NamedMark(t1 *testing.T) {
+	j := NewJournal(New[int]())
+	j.NamedMark("empty")
+	j.Insert(1, 1)
+	j.NamedMark("one")
+	j.Insert(1, 10)
+
+	tests := []struct {
+		name    string
+		mark    string
+		want    []element[int]
+		wantErr bool
+	}{
+		{name: "back to one", mark: "one", want: []element[int]{{key: 1, value: 1}}},
+		{name: "back to empty", mark: "empty", want: []element[int]{}},
+		{name: "forward to one", mark: "one", want: []element[int]{{key: 1, value: 1}}},
+		{name: "unknown mark", mark: "two", want: []element[int]{{key: 1, value: 1}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			if err := j.RollbackToNamed(tt.mark); (err != nil) != tt.wantErr {
+				t1.Errorf("RollbackToNamed(%q) error = %v, wantErr %v", tt.mark, err, tt.wantErr)
+			}
+			if got := j.Tree().elements(); !reflect.DeepEqual(got, tt.want) {
+				t1.Errorf("tree = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func treeWithValue(t *Tree[int], key int, value any) *Tree[int] {
+	t.Insert(key, value)
+	return t
+}
@@ -0,0 +1,176 @@
+package rbtree
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+// jsonElement is the structure of element in JSON array
+type jsonElement[V constraints.Ordered] struct {
+	Key   V   `json:"key"`
+	Value any `json:"value"`
+}
+
+// gobElement is the structure of element in gob encoding
+type gobElement[V constraints.Ordered] struct {
+	Key   V
+	Value any
+}
+
+// MarshalJSON is a function for encoding tree to JSON (json.Marshaler).
+// Tree with string keys is encoded as object, other trees as array of {"key": ..., "value": ...} objects.
+// Elements are always written in key order.
+func (t *Tree[V]) MarshalJSON() ([]byte, error) {
+	t.guard.startRead()
+	defer t.guard.endRead()
+
+	stringKeys := isStringKind[V]()
+	var buf bytes.Buffer
+	if stringKeys {
+		buf.WriteByte('{')
+	} else {
+		buf.WriteByte('[')
+	}
+
+	var err error
+	first := true
+	t.Ascend(func(key V, value any) bool {
+		var k, v []byte
+		if k, err = json.Marshal(key); err != nil {
+			return false
+		}
+		if v, err = json.Marshal(value); err != nil {
+			return false
+		}
+
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+
+		if stringKeys {
+			buf.Write(k)
+			buf.WriteByte(':')
+			buf.Write(v)
+			return true
+		}
+		buf.WriteString(`{"key":`)
+		buf.Write(k)
+		buf.WriteString(`,"value":`)
+		buf.Write(v)
+		buf.WriteByte('}')
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if stringKeys {
+		buf.WriteByte('}')
+	} else {
+		buf.WriteByte(']')
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON is a function for decoding tree from JSON written by MarshalJSON (json.Unmarshaler).
+// All elements of tree are replaced by decoded elements, values are decoded as by json.Unmarshal to any.
+// If key is repeated, the last value is used.
+func (t *Tree[V]) UnmarshalJSON(data []byte) error {
+	var elements []element[V]
+	if isStringKind[V]() {
+		var object map[string]any
+		if err := json.Unmarshal(data, &object); err != nil {
+			return err
+		}
+		for k, value := range object {
+			var key V
+			reflect.ValueOf(&key).Elem().SetString(k)
+			elements = append(elements, element[V]{key: key, value: value})
+		}
+	} else {
+		var array []jsonElement[V]
+		if err := json.Unmarshal(data, &array); err != nil {
+			return err
+		}
+		for _, e := range array {
+			elements = append(elements, element[V]{key: e.Key, value: e.Value})
+		}
+	}
+
+	t.init()
+	t.guard.startWrite()
+	defer t.guard.endWrite()
+	t.build(sortElements(elements))
+
+	return nil
+}
+
+// GobEncode is a function for encoding tree by encoding/gob (gob.GobEncoder).
+// Types of values except basic types should be registered by gob.Register.
+func (t *Tree[V]) GobEncode() ([]byte, error) {
+	t.guard.startRead()
+	defer t.guard.endRead()
+
+	elements := make([]gobElement[V], 0, t.size)
+	t.Ascend(func(key V, value any) bool {
+		elements = append(elements, gobElement[V]{Key: key, Value: value})
+		return true
+	})
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(elements); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// GobDecode is a function for decoding tree encoded by GobEncode (gob.GobDecoder).
+// All elements of tree are replaced by decoded elements.
+func (t *Tree[V]) GobDecode(data []byte) error {
+	var decoded []gobElement[V]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&decoded); err != nil {
+		return err
+	}
+
+	elements := make([]element[V], 0, len(decoded))
+	for _, e := range decoded {
+		elements = append(elements, element[V]{key: e.Key, value: e.Value})
+	}
+
+	t.init()
+	t.guard.startWrite()
+	defer t.guard.endWrite()
+	t.build(sortElements(elements))
+
+	return nil
+}
+
+// sortElements - internal function for sorting elements by key and removing repeated keys (the last element wins)
+func sortElements[V constraints.Ordered](elements []element[V]) []element[V] {
+	sort.SliceStable(elements, func(i, j int) bool { return elements[i].key < elements[j].key })
+
+	result := elements[:0]
+	for _, e := range elements {
+		if len(result) > 0 && result[len(result)-1].key == e.key {
+			result[len(result)-1] = e
+			continue
+		}
+		result = append(result, e)
+	}
+
+	return result
+}
+
+// isStringKind - internal function for checking that keys of type V are strings
+func isStringKind[V constraints.Ordered]() bool {
+	var key V
+	return reflect.TypeOf(key).Kind() == reflect.String
+}
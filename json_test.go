@@ -0,0 +1,85 @@
+package rbtree
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestTree_MarshalJSON(t1 *testing.T) {
+	ints := getTree([]int{22, 8, 4})
+	data, err := json.Marshal(ints)
+	if err != nil {
+		t1.Fatalf("Marshal() error = %v", err)
+	}
+	if want := `[{"key":4,"value":4},{"key":8,"value":8},{"key":22,"value":22}]`; string(data) != want {
+		t1.Errorf("Marshal() = %s, want %s", data, want)
+	}
+
+	strings := New[string]()
+	strings.Insert("b", []int{1})
+	strings.Insert("a", "x")
+	strings.Insert("c", nil)
+	data, err = json.Marshal(strings)
+	if err != nil {
+		t1.Fatalf("Marshal() error = %v", err)
+	}
+	if want := `{"a":"x","b":[1],"c":null}`; string(data) != want {
+		t1.Errorf("Marshal() = %s, want %s", data, want)
+	}
+
+	empty, _ := json.Marshal(New[int]())
+	if string(empty) != "[]" {
+		t1.Errorf("Marshal() of empty tree = %s", empty)
+	}
+}
+
+func TestTree_UnmarshalJSON(t1 *testing.T) {
+	var ints Tree[int]
+	err := json.Unmarshal([]byte(`[{"key":8,"value":"a"},{"key":4,"value":1},{"key":8,"value":"b"}]`), &ints)
+	if err != nil {
+		t1.Fatalf("Unmarshal() error = %v", err)
+	}
+	checkTreeProperties(t1, &ints)
+	want := []element[int]{{key: 4, value: float64(1)}, {key: 8, value: "b"}}
+	if !reflect.DeepEqual(ints.elements(), want) {
+		t1.Errorf("Unmarshal() = %v, want %v", ints.elements(), want)
+	}
+
+	strings := New[string]()
+	strings.Insert("old", 1)
+	if err := json.Unmarshal([]byte(`{"b":true,"a":"x"}`), strings); err != nil {
+		t1.Fatalf("Unmarshal() error = %v", err)
+	}
+	wantStrings := []element[string]{{key: "a", value: "x"}, {key: "b", value: true}}
+	if !reflect.DeepEqual(strings.elements(), wantStrings) {
+		t1.Errorf("Unmarshal() = %v, want %v", strings.elements(), wantStrings)
+	}
+
+	if err := json.Unmarshal([]byte(`{"a":1}`), New[int]()); err == nil {
+		t1.Errorf("Unmarshal() of object to int tree without error")
+	}
+}
+
+func TestTree_Gob(t1 *testing.T) {
+	type state struct {
+		Tree *Tree[int]
+	}
+	t := getTree([]int{22, 8, 4})
+	t.Insert(1, "one")
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(state{Tree: t}); err != nil {
+		t1.Fatalf("Encode() error = %v", err)
+	}
+	var got state
+	if err := gob.NewDecoder(&buf).Decode(&got); err != nil {
+		t1.Fatalf("Decode() error = %v", err)
+	}
+	checkTreeProperties(t1, got.Tree)
+	if !reflect.DeepEqual(got.Tree.elements(), t.elements()) {
+		t1.Errorf("Decode() = %v, want %v", got.Tree.elements(), t.elements())
+	}
+}
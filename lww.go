@@ -0,0 +1,156 @@
+package rbtree
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/exp/constraints"
+)
+
+// LWWTag is the structure of version of LWWTree's entry.
+// Tags are ordered by Time, tags with the same Time - by Replica
+type LWWTag struct {
+	Time    int64
+	Replica string
+}
+
+// LWWEntry is the structure of LWWTree's entry: value or tombstone of deleted element with its tag
+type LWWEntry struct {
+	Value   any
+	Tag     LWWTag
+	Deleted bool
+}
+
+// LWWTree is an ordered map for replicas which are changed independently and merged later (CRDT).
+// Every entry is tagged by LWWTag, deleted elements are kept as tombstones.
+// Merge keeps entry with the greatest tag for every key (last writer wins),
+// so it's commutative, associative and idempotent: replicas which merged the same changes are identical.
+// LWWTree is not safe for concurrent use
+type LWWTree[V constraints.Ordered] struct {
+	tree    *Tree[V] // values are LWWEntry
+	replica string
+	clock   int64 // the greatest time of tags seen by replica
+	live    int
+	now     func() int64
+}
+
+// Less is a function for comparing tags
+func (t LWWTag) Less(other LWWTag) bool {
+	return t.Time < other.Time || t.Time == other.Time && t.Replica < other.Replica
+}
+
+// NewLWWTree is a function for creation empty replica
+// - param replica is unique id of replica, it breaks ties of tags with the same time
+func NewLWWTree[V constraints.Ordered](replica string) *LWWTree[V] {
+	return &LWWTree[V]{
+		tree:    New[V](),
+		replica: replica,
+		now: func() int64 {
+			return time.Now().UnixNano()
+		},
+	}
+}
+
+// Insert is a function for inserting element into replica.
+// If element with the same key exists, its value is replaced.
+// Tag of element is greater than tags of all entries seen by replica.
+// - param key should be `ordered type` (`int`, `string`, `float` etc.)
+// - param value can be any type
+func (t *LWWTree[V]) Insert(key V, value any) {
+	t.set(key, LWWEntry{Value: value, Tag: t.tick()})
+}
+
+// Delete is a function for deleting element from replica.
+// Tombstone is inserted even if element doesn't exist: it wins over older inserts of other replicas.
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *LWWTree[V]) Delete(key V) {
+	t.set(key, LWWEntry{Tag: t.tick(), Deleted: true})
+}
+
+// Exists is a function for searching element in replica. If element exists and isn't deleted - return true, else - false
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *LWWTree[V]) Exists(key V) bool {
+	n := t.tree.search(key)
+
+	return n != nil && !n.element.value.(LWWEntry).Deleted
+}
+
+// GetValue is a function for searching element in replica and returning value of this element
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *LWWTree[V]) GetValue(key V) (any, error) {
+	var result any
+	n := t.tree.search(key)
+	if n == nil || n.element.value.(LWWEntry).Deleted {
+		return result, errors.New(fmt.Sprintf("element with key %v not found", key))
+	}
+
+	return n.element.value.(LWWEntry).Value, nil
+}
+
+// Len is a function for getting count of not deleted elements in replica.
+func (t *LWWTree[V]) Len() int {
+	return t.live
+}
+
+// Ascend is a function for iterating over not deleted elements in key order.
+// Iteration stops when fn returns false.
+func (t *LWWTree[V]) Ascend(fn func(key V, value any) bool) {
+	t.tree.Ascend(func(key V, value any) bool {
+		e := value.(LWWEntry)
+		return e.Deleted || fn(key, e.Value)
+	})
+}
+
+// Entries is a function for iterating over all entries including tombstones in key order.
+// Entries are used for sending replica to other process, where they are merged by MergeEntry.
+// Iteration stops when fn returns false.
+func (t *LWWTree[V]) Entries(fn func(key V, e LWWEntry) bool) {
+	t.tree.Ascend(func(key V, value any) bool {
+		return fn(key, value.(LWWEntry))
+	})
+}
+
+// Merge is a function for merging all entries of other replica into replica. Other replica isn't changed.
+func (t *LWWTree[V]) Merge(other *LWWTree[V]) {
+	other.Entries(func(key V, e LWWEntry) bool {
+		t.MergeEntry(key, e)
+		return true
+	})
+}
+
+// MergeEntry is a function for merging one entry of other replica.
+// Entry replaces existing entry if its tag is greater.
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *LWWTree[V]) MergeEntry(key V, e LWWEntry) {
+	if e.Tag.Time > t.clock {
+		t.clock = e.Tag.Time
+	}
+
+	if n := t.tree.search(key); n != nil && !n.element.value.(LWWEntry).Tag.Less(e.Tag) {
+		return
+	}
+	t.set(key, e)
+}
+
+// tick - internal function for getting tag of new local change
+func (t *LWWTree[V]) tick() LWWTag {
+	now := t.now()
+	if now <= t.clock {
+		now = t.clock + 1
+	}
+	t.clock = now
+
+	return LWWTag{Time: now, Replica: t.replica}
+}
+
+// set - internal function for replacing entry and counting not deleted elements
+func (t *LWWTree[V]) set(key V, e LWWEntry) {
+	old, existed := t.tree.put(key, e)
+	if existed && !old.(LWWEntry).Deleted {
+		t.live--
+	}
+	if !e.Deleted {
+		t.live++
+	}
+}
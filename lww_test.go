@@ -0,0 +1,167 @@
+package rbtree
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestLWWTree_InsertDelete(t1 *testing.T) {
+	t := NewLWWTree[int]("a")
+	t.Insert(1, "one")
+	t.Insert(2, "two")
+	t.Insert(1, "uno")
+	t.Delete(2)
+	t.Delete(3)
+
+	if value, err := t.GetValue(1); err != nil || value != "uno" {
+		t1.Errorf("GetValue(1) = %v, %v, want uno", value, err)
+	}
+	if t.Exists(2) || t.Exists(3) {
+		t1.Errorf("deleted elements exist")
+	}
+	if _, err := t.GetValue(2); err == nil {
+		t1.Errorf("GetValue(2) of deleted element error = nil")
+	}
+	if t.Len() != 1 {
+		t1.Errorf("Len() = %v, want 1", t.Len())
+	}
+
+	tombstones := 0
+	t.Entries(func(key int, e LWWEntry) bool {
+		if e.Deleted {
+			tombstones++
+		}
+		return true
+	})
+	if tombstones != 2 {
+		t1.Errorf("Entries() has %v tombstones, want 2", tombstones)
+	}
+}
+
+func TestLWWTree_Merge(t1 *testing.T) {
+	tests := []struct {
+		name string
+		ops  func(a, b *LWWTree[int])
+		want map[int]any
+	}{
+		{
+			name: "later insert wins",
+			ops: func(a, b *LWWTree[int]) {
+				a.Insert(1, "a")
+				b.Insert(1, "b")
+			},
+			want: map[int]any{1: "b"},
+		},
+		{
+			name: "later delete wins",
+			ops: func(a, b *LWWTree[int]) {
+				a.Insert(1, "a")
+				b.Delete(1)
+			},
+			want: map[int]any{},
+		},
+		{
+			name: "later insert wins over delete",
+			ops: func(a, b *LWWTree[int]) {
+				b.Delete(1)
+				a.Insert(1, "a")
+			},
+			want: map[int]any{1: "a"},
+		},
+		{
+			name: "change after merge wins",
+			ops: func(a, b *LWWTree[int]) {
+				// clock of b is behind, but merged tags move it forward
+				a.now = func() int64 { return 1000 }
+				a.Insert(1, "a")
+				b.Merge(a)
+				b.Insert(1, "b")
+			},
+			want: map[int]any{1: "b"},
+		},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			clock := int64(0)
+			a, b := newTestLWWTree("a", &clock), newTestLWWTree("b", &clock)
+			tt.ops(a, b)
+
+			a.Merge(b)
+			b.Merge(a)
+			for _, t := range []*LWWTree[int]{a, b} {
+				got := map[int]any{}
+				t.Ascend(func(key int, value any) bool {
+					got[key] = value
+					return true
+				})
+				if !reflect.DeepEqual(got, tt.want) {
+					t1.Errorf("replica %v = %v, want %v", t.replica, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestLWWTree_Merge_convergence(t1 *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	replicas := make([]*LWWTree[int], 3)
+	for i := range replicas {
+		clock := int64(rng.Intn(100))
+		replicas[i] = newTestLWWTree(fmt.Sprint(i), &clock)
+	}
+	for i := 0; i < 3000; i++ {
+		t := replicas[rng.Intn(len(replicas))]
+		key := rng.Intn(100)
+		switch rng.Intn(10) {
+		case 0:
+			t.Merge(replicas[rng.Intn(len(replicas))])
+		case 1, 2, 3:
+			t.Delete(key)
+		default:
+			t.Insert(key, i)
+		}
+	}
+
+	// merge in different orders and groupings
+	ab := entriesOf(merged(merged(replicas[0], replicas[1]), replicas[2]))
+	ba := entriesOf(merged(replicas[2], merged(replicas[1], replicas[0])))
+	twice := merged(merged(replicas[1], replicas[2]), replicas[0])
+	twice.Merge(replicas[2])
+	twice.Merge(twice)
+	if !reflect.DeepEqual(ab, ba) || !reflect.DeepEqual(ab, entriesOf(twice)) {
+		t1.Errorf("replicas merged in different orders are different")
+	}
+}
+
+// newTestLWWTree creates replica with clock which ticks by 1 on every call
+func newTestLWWTree(replica string, clock *int64) *LWWTree[int] {
+	t := NewLWWTree[int](replica)
+	t.now = func() int64 {
+		*clock++
+		return *clock
+	}
+
+	return t
+}
+
+// merged returns new replica with entries of a and b
+func merged(a, b *LWWTree[int]) *LWWTree[int] {
+	result := NewLWWTree[int]("merged")
+	result.Merge(a)
+	result.Merge(b)
+
+	return result
+}
+
+// entriesOf returns all entries of replica
+func entriesOf(t *LWWTree[int]) map[int]LWWEntry {
+	result := map[int]LWWEntry{}
+	t.Entries(func(key int, e LWWEntry) bool {
+		result[key] = e
+		return true
+	})
+
+	return result
+}
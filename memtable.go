@@ -0,0 +1,119 @@
+package rbtree
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Memtable is a goroutine-safe tree for LSM-like stores.
+// When estimated size of its tree reaches threshold, the tree is frozen and handed over to flush callback,
+// while a fresh tree takes new writes. Frozen trees are readable through Memtable until flush returns.
+// Deleted keys are kept as Tombstone values, so they hide older values of frozen trees and reach flush callback
+type Memtable[V constraints.Ordered] struct {
+	mu        sync.RWMutex
+	active    *Tree[V]
+	flushing  []*Tree[V] // frozen trees from the oldest to the newest
+	threshold int
+	flush     func(frozen *Tree[V])
+}
+
+// Tombstone is the value of Memtable's element with deleted key
+type Tombstone struct{}
+
+// NewMemtable is a function for creation empty memtable
+// - param threshold is estimated size of tree in bytes (see Tree.Bytes) which triggers flush, it should be > 0
+// - param flush is called with frozen tree in goroutine of write which reached threshold,
+// frozen tree must not be changed, its elements with Tombstone values are deleted keys
+func NewMemtable[V constraints.Ordered](threshold int, flush func(frozen *Tree[V])) (*Memtable[V], error) {
+	if threshold <= 0 {
+		return nil, errors.New(fmt.Sprintf("threshold %v should be > 0", threshold))
+	}
+
+	return &Memtable[V]{
+		active:    New[V](),
+		threshold: threshold,
+		flush:     flush,
+	}, nil
+}
+
+// Insert is a function for inserting element into memtable.
+// If element with the same key exists, its value is replaced.
+// - param key should be `ordered type` (`int`, `string`, `float` etc.)
+// - param value can be any type
+func (m *Memtable[V]) Insert(key V, value any) {
+	m.mu.Lock()
+	m.active.Insert(key, value)
+	if m.active.Bytes() < m.threshold {
+		m.mu.Unlock()
+		return
+	}
+	m.rotate()
+}
+
+// Delete is a function for deleting element from memtable: Tombstone is inserted into active tree
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (m *Memtable[V]) Delete(key V) {
+	m.Insert(key, Tombstone{})
+}
+
+// GetValue is a function for searching element in active tree and then in frozen trees which are being flushed.
+// The newest tree with key decides: deleted key isn't found even if older tree has its value
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (m *Memtable[V]) GetValue(key V) (any, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	value, err := m.active.GetValue(key)
+	for i := len(m.flushing) - 1; i >= 0 && err != nil; i-- {
+		value, err = m.flushing[i].GetValue(key)
+	}
+	if _, deleted := value.(Tombstone); deleted {
+		return nil, errors.New(fmt.Sprintf("element with key %v not found", key))
+	}
+
+	return value, err
+}
+
+// Bytes is a function for getting estimated size of active tree in bytes.
+func (m *Memtable[V]) Bytes() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.active.Bytes()
+}
+
+// Flush is a function for handing over active tree to flush callback regardless of its size.
+// Empty tree is not flushed.
+func (m *Memtable[V]) Flush() {
+	m.mu.Lock()
+	if m.active.Len() == 0 {
+		m.mu.Unlock()
+		return
+	}
+	m.rotate()
+}
+
+// rotate - internal function for freezing active tree and calling flush without holding lock.
+// Write lock should be held, it's released by function. Frozen tree is removed even if flush panics
+func (m *Memtable[V]) rotate() {
+	frozen := m.active
+	frozen.guard.frozen = true
+	m.active = New[V]()
+	m.flushing = append(m.flushing, frozen)
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		for i, t := range m.flushing {
+			if t == frozen {
+				m.flushing = append(m.flushing[:i], m.flushing[i+1:]...)
+				break
+			}
+		}
+	}()
+	m.flush(frozen)
+}
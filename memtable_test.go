@@ -0,0 +1,152 @@
+package rbtree
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+type sizedValue struct {
+	size int
+}
+
+func (v sizedValue) Size() int {
+	return v.size
+}
+
+func TestTree_Bytes(t1 *testing.T) {
+	t := New[string]()
+	empty := t.Bytes()
+	t.Insert("key", strings.Repeat("v", 100))
+	one := t.Bytes()
+	if one-empty < 103 {
+		t1.Errorf("Bytes() after insert = %v, want at least %v", one, empty+103)
+	}
+
+	t.Insert("key", sizedValue{size: 1000})
+	if got := t.Bytes() - one; got != 900 {
+		t1.Errorf("Bytes() changed by %v after update, want 900", got)
+	}
+
+	t.Insert("other", []byte("abc"))
+	t.Delete("key")
+	t.Delete("other")
+	if t.Bytes() != empty {
+		t1.Errorf("Bytes() of empty tree = %v, want %v", t.Bytes(), empty)
+	}
+
+	t.Insert("a", 1)
+	t.Insert("b", 2)
+	before := t.Bytes()
+	right := t.Split("b")
+	if t.Bytes()+right.Bytes() != before {
+		t1.Errorf("Bytes() after Split() = %v + %v, want %v", t.Bytes(), right.Bytes(), before)
+	}
+}
+
+func TestMemtable(t1 *testing.T) {
+	var mu sync.Mutex
+	var flushed []*Tree[int]
+	var m *Memtable[int]
+	m, _ = NewMemtable[int](1000, func(frozen *Tree[int]) {
+		// frozen tree is still readable through memtable during flush
+		if _, err := m.GetValue(frozen.Min()); err != nil {
+			t1.Errorf("element of frozen tree isn't readable during flush")
+		}
+		mu.Lock()
+		flushed = append(flushed, frozen)
+		mu.Unlock()
+	})
+
+	for i := 0; i < 100; i++ {
+		m.Insert(i, i)
+	}
+	if len(flushed) == 0 {
+		t1.Fatalf("memtable wasn't flushed")
+	}
+
+	total := m.active.Len()
+	for _, t := range flushed {
+		if t.Bytes() < 1000 {
+			t1.Errorf("tree of %v bytes was flushed", t.Bytes())
+		}
+		total += t.Len()
+	}
+	if total != 100 {
+		t1.Errorf("memtable lost elements, %v elements", total)
+	}
+	if len(m.flushing) != 0 {
+		t1.Errorf("flushed trees are still in memtable")
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t1.Errorf("write to frozen tree without panic")
+			}
+		}()
+		flushed[0].Insert(-1, -1)
+	}()
+
+	m.Flush()
+	if m.Bytes() != New[int]().Bytes() {
+		t1.Errorf("Flush() didn't hand over active tree")
+	}
+}
+
+func TestMemtable_Delete(t1 *testing.T) {
+	var flushed []*Tree[string]
+	var m *Memtable[string]
+	m, _ = NewMemtable[string](1<<20, func(frozen *Tree[string]) {
+		flushed = append(flushed, frozen)
+		if len(flushed) > 1 {
+			return
+		}
+		// frozen tree with old value is readable through memtable during flush
+		m.Delete("a")
+		if _, err := m.GetValue("a"); err == nil {
+			t1.Errorf("GetValue() of deleted key without error")
+		}
+		if value, err := m.GetValue("b"); err != nil || value != 2 {
+			t1.Errorf("GetValue() = %v, %v, want 2", value, err)
+		}
+	})
+	m.Insert("a", 1)
+	m.Insert("b", 2)
+	m.Flush()
+	m.Flush()
+
+	if len(flushed) != 2 {
+		t1.Fatalf("%v trees were flushed, want 2", len(flushed))
+	}
+	if value, err := flushed[1].GetValue("a"); err != nil || value != (Tombstone{}) {
+		t1.Errorf("flushed tree has %v, %v for deleted key, want tombstone", value, err)
+	}
+}
+
+func TestMemtable_flushPanic(t1 *testing.T) {
+	m, _ := NewMemtable[int](1<<20, func(frozen *Tree[int]) {
+		panic("flush failed")
+	})
+	m.Insert(1, 1)
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t1.Errorf("Flush() without panic")
+			}
+		}()
+		m.Flush()
+	}()
+
+	if len(m.flushing) != 0 {
+		t1.Errorf("frozen tree is still in memtable after panic of flush")
+	}
+}
+
+func TestNewMemtable_threshold(t1 *testing.T) {
+	for _, threshold := range []int{0, -1} {
+		if _, err := NewMemtable[int](threshold, func(frozen *Tree[int]) {}); err == nil {
+			t1.Errorf("NewMemtable(%v) without error", threshold)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package rbtree
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Hash is a hash of set of tree's elements.
+// Hash of set is the sum of hashes of its elements modulo 2^128,
+// so it doesn't depend on shape of tree and the same elements have the same hash in any tree
+type Hash [16]byte
+
+// KeyRange is the structure of range of keys [Lo, Hi).
+// Range has no lower bound if HasLo is false and no upper bound if HasHi is false,
+// zero KeyRange contains all keys
+type KeyRange[V constraints.Ordered] struct {
+	Lo, Hi       V
+	HasLo, HasHi bool
+}
+
+// merkleNode is the structure of node's hashes: hash of node's element and hash of all elements of subtree
+type merkleNode struct {
+	self Hash
+	sum  Hash
+}
+
+// Contains is a function for checking that key is in range
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (r KeyRange[V]) Contains(key V) bool {
+	return (!r.HasLo || key >= r.Lo) && (!r.HasHi || key < r.Hi)
+}
+
+// RootHash is a function for getting hash of all elements of tree in O(1).
+// Trees with the same elements have the same hash. Tree should be created with WithMerkle option.
+func (t *Tree[V]) RootHash() Hash {
+	t.guard.startRead()
+	defer t.guard.endRead()
+	t.mustMerkle()
+
+	return sumOf(t.root)
+}
+
+// RangeHash is a function for getting hash of elements with keys in range r in O(log n).
+// Tree should be created with WithMerkle option.
+func (t *Tree[V]) RangeHash(r KeyRange[V]) Hash {
+	t.guard.startRead()
+	defer t.guard.endRead()
+	t.mustMerkle()
+
+	return t.rangeHash(r)
+}
+
+// MerkleDiff is a function for searching key ranges where tree differs from remote tree.
+// remote returns RangeHash of remote tree, it's usually a request to other process.
+// Ranges with different hashes are split by keys of tree until they have at most leafLen keys of tree,
+// so remote is called O(d log n) times for d differences. Adjacent ranges are merged.
+// Ranges are split by keys of tree only: range can have any count of keys of remote tree,
+// running MerkleDiff on remote side too gives ranges split by its keys.
+// Tree should be created with WithMerkle option.
+func (t *Tree[V]) MerkleDiff(remote func(r KeyRange[V]) (Hash, error), leafLen int) ([]KeyRange[V], error) {
+	t.guard.startRead()
+	defer t.guard.endRead()
+	t.mustMerkle()
+
+	var result []KeyRange[V]
+	err := t.merkleDiff(KeyRange[V]{}, remote, leafLen, &result)
+
+	return result, err
+}
+
+// merkleDiff - internal function for comparing range r with remote and splitting it by key of the highest node in r
+func (t *Tree[V]) merkleDiff(r KeyRange[V], remote func(r KeyRange[V]) (Hash, error), leafLen int, result *[]KeyRange[V]) error {
+	theirs, err := remote(r)
+	if err != nil {
+		return err
+	}
+	if theirs == t.rangeHash(r) {
+		return nil
+	}
+
+	n := t.root
+	for n != t.nilNode {
+		if r.HasLo && n.element.key <= r.Lo {
+			n = n.right
+			continue
+		}
+		if r.HasHi && n.element.key >= r.Hi {
+			n = n.left
+			continue
+		}
+		break
+	}
+
+	if n == t.nilNode || t.countRange(r, leafLen+1) <= leafLen {
+		if last := len(*result) - 1; last >= 0 && (*result)[last].HasHi && r.HasLo && (*result)[last].Hi == r.Lo {
+			(*result)[last].Hi, (*result)[last].HasHi = r.Hi, r.HasHi
+			return nil
+		}
+		*result = append(*result, r)
+		return nil
+	}
+
+	left, right := r, r
+	left.Hi, left.HasHi = n.element.key, true
+	right.Lo, right.HasLo = n.element.key, true
+	if err := t.merkleDiff(left, remote, leafLen, result); err != nil {
+		return err
+	}
+
+	return t.merkleDiff(right, remote, leafLen, result)
+}
+
+// rangeHash - internal function for getting hash of elements in range r
+func (t *Tree[V]) rangeHash(r KeyRange[V]) Hash {
+	result := sumOf(t.root)
+	if r.HasHi {
+		result = t.hashLess(r.Hi)
+	}
+	if r.HasLo {
+		result = subHash(result, t.hashLess(r.Lo))
+	}
+
+	return result
+}
+
+// hashLess - internal function for getting hash of elements with keys less than key
+func (t *Tree[V]) hashLess(key V) Hash {
+	var result Hash
+	n := t.root
+	for n != t.nilNode {
+		if n.element.key < key {
+			result = addHash(addHash(result, sumOf(n.left)), n.ext.merkle.self)
+			n = n.right
+			continue
+		}
+		n = n.left
+	}
+
+	return result
+}
+
+// countRange - internal function for counting elements in range r, counting stops at limit
+func (t *Tree[V]) countRange(r KeyRange[V], limit int) int {
+	var n *node[V]
+	switch {
+	case r.HasLo:
+		n = t.ceiling(r.Lo, false)
+	case t.root != t.nilNode:
+		n = t.min(t.root)
+	}
+
+	count := 0
+	t.ascendFrom(n, func(key V, value any) bool {
+		if !r.Contains(key) {
+			return false
+		}
+		count++
+		return count < limit
+	})
+
+	return count
+}
+
+// rehash - internal function for recomputing all merkle hashes of subtree n, e.g. after change of codecs
+func (t *Tree[V]) rehash(n *node[V]) {
+	if n == t.nilNode {
+		return
+	}
+
+	t.rehash(n.left)
+	t.rehash(n.right)
+	n.ext.merkle.self = t.elementHash(n.element.key, n.element.value)
+	pullMerkle(n)
+}
+
+// elementHash - internal function for hashing binary encoding of element.
+// It panics if element can't be encoded: tree's methods which change elements don't return errors.
+func (t *Tree[V]) elementHash(key V, value any) Hash {
+	b, err := appendElement(nil, t.getCodecs(), key, value)
+	if err != nil {
+		panic(fmt.Sprintf("rbtree: can't hash element with key %v: %v", key, err))
+	}
+
+	var result Hash
+	sum := sha256.Sum256(b)
+	copy(result[:], sum[:])
+
+	return result
+}
+
+// mustMerkle - internal function for checking that tree has merkle hashes
+func (t *Tree[V]) mustMerkle() {
+	if !t.merkle {
+		panic("rbtree: tree has no merkle hashes, use WithMerkle option")
+	}
+}
+
+// pullMerkle - internal function for recomputing merkle hash of n's subtree from its children
+func pullMerkle[V constraints.Ordered](n *node[V]) {
+	n.ext.merkle.sum = addHash(addHash(sumOf(n.left), n.ext.merkle.self), sumOf(n.right))
+}
+
+// sumOf - internal function for getting merkle hash of subtree, hash of nilNode is zero
+func sumOf[V constraints.Ordered](n *node[V]) Hash {
+	if n.ext == nil {
+		return Hash{}
+	}
+
+	return n.ext.merkle.sum
+}
+
+// addHash - internal function for adding hashes as 128-bit numbers
+func addHash(a, b Hash) Hash {
+	lo, carry := bits.Add64(binary.BigEndian.Uint64(a[8:]), binary.BigEndian.Uint64(b[8:]), 0)
+	hi, _ := bits.Add64(binary.BigEndian.Uint64(a[:8]), binary.BigEndian.Uint64(b[:8]), carry)
+
+	var result Hash
+	binary.BigEndian.PutUint64(result[:8], hi)
+	binary.BigEndian.PutUint64(result[8:], lo)
+
+	return result
+}
+
+// subHash - internal function for subtracting hashes as 128-bit numbers
+func subHash(a, b Hash) Hash {
+	lo, borrow := bits.Sub64(binary.BigEndian.Uint64(a[8:]), binary.BigEndian.Uint64(b[8:]), 0)
+	hi, _ := bits.Sub64(binary.BigEndian.Uint64(a[:8]), binary.BigEndian.Uint64(b[:8]), borrow)
+
+	var result Hash
+	binary.BigEndian.PutUint64(result[:8], hi)
+	binary.BigEndian.PutUint64(result[8:], lo)
+
+	return result
+}
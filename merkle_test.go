@@ -0,0 +1,153 @@
+package rbtree
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"golang.org/x/exp/constraints"
+)
+
+func TestTree_RootHash(t1 *testing.T) {
+	t := New[int](WithMerkle())
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 2000; i++ {
+		key := rng.Intn(300)
+		if rng.Intn(3) == 0 {
+			t.Delete(key)
+		} else {
+			t.Insert(key, rng.Intn(5))
+		}
+		checkMerkle(t1, t, t.root)
+	}
+
+	other := New[int](WithMerkle())
+	for _, e := range t.elements() {
+		other.Insert(e.key, e.value)
+	}
+	if t.RootHash() != other.RootHash() {
+		t1.Errorf("RootHash() of trees with the same elements are different")
+	}
+
+	right := t.Split(150)
+	checkMerkle(t1, t, t.root)
+	checkMerkle(t1, right, right.root)
+	mustNoErr(t1, t.Join(right))
+	if t.RootHash() != other.RootHash() {
+		t1.Errorf("RootHash() after Split and Join = %x, want %x", t.RootHash(), other.RootHash())
+	}
+
+	other.Insert(other.Max(), "changed")
+	if t.RootHash() == other.RootHash() {
+		t1.Errorf("RootHash() of trees with different values are equal")
+	}
+}
+
+func TestTree_RangeHash(t1 *testing.T) {
+	t := New[int](WithMerkle())
+	for i := 0; i < 100; i += 2 {
+		t.Insert(i, i)
+	}
+
+	tests := []struct {
+		name string
+		r    KeyRange[int]
+	}{
+		{name: "all keys", r: KeyRange[int]{}},
+		{name: "lower bound", r: KeyRange[int]{Lo: 31, HasLo: true}},
+		{name: "upper bound", r: KeyRange[int]{Hi: 50, HasHi: true}},
+		{name: "both bounds", r: KeyRange[int]{Lo: 10, Hi: 20, HasLo: true, HasHi: true}},
+		{name: "empty range", r: KeyRange[int]{Lo: 11, Hi: 12, HasLo: true, HasHi: true}},
+		{name: "out of keys", r: KeyRange[int]{Lo: 200, HasLo: true}},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			var want Hash
+			t.Ascend(func(key int, value any) bool {
+				if tt.r.Contains(key) {
+					want = addHash(want, t.elementHash(key, value))
+				}
+				return true
+			})
+			if got := t.RangeHash(tt.r); got != want {
+				t1.Errorf("RangeHash() = %x, want %x", got, want)
+			}
+		})
+	}
+}
+
+func TestTree_MerkleDiff(t1 *testing.T) {
+	local := New[int](WithMerkle())
+	remote := New[int](WithMerkle())
+	for i := 0; i < 1000; i++ {
+		local.Insert(i, i)
+		remote.Insert(999-i, 999-i)
+	}
+
+	calls := 0
+	hashes := func(r KeyRange[int]) (Hash, error) {
+		calls++
+		return remote.RangeHash(r), nil
+	}
+	got, err := local.MerkleDiff(hashes, 8)
+	if err != nil || len(got) != 0 || calls != 1 {
+		t1.Errorf("MerkleDiff() of equal trees = %v, %v with %v calls", got, err, calls)
+	}
+
+	changed := []int{0, 17, 500, 501, 998}
+	remote.Insert(17, "changed")
+	remote.Insert(500, "changed")
+	remote.Delete(501)
+	remote.Delete(998)
+	remote.Insert(-5, -5)
+	local.Delete(0)
+
+	calls = 0
+	got, err = local.MerkleDiff(hashes, 8)
+	mustNoErr(t1, err)
+	if calls > 200 {
+		t1.Errorf("MerkleDiff() called remote %v times", calls)
+	}
+	for _, key := range append(changed, -5) {
+		found := false
+		for _, r := range got {
+			found = found || r.Contains(key)
+		}
+		if !found {
+			t1.Errorf("MerkleDiff() = %v, changed key %v is not in ranges", got, key)
+		}
+	}
+	if len(got) > len(changed)+1 {
+		t1.Errorf("MerkleDiff() = %v, want at most %v ranges", got, len(changed)+1)
+	}
+
+	wantErr := errors.New("connection is closed")
+	_, err = local.MerkleDiff(func(r KeyRange[int]) (Hash, error) { return Hash{}, wantErr }, 8)
+	if !errors.Is(err, wantErr) {
+		t1.Errorf("MerkleDiff() error = %v, want %v", err, wantErr)
+	}
+}
+
+func TestTree_RootHash_without_merkle(t1 *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t1.Errorf("RootHash() of tree without WithMerkle doesn't panic")
+		}
+	}()
+	New[int]().RootHash()
+}
+
+// checkMerkle checks merkle hashes of all nodes of subtree n and returns hash of subtree
+func checkMerkle[V constraints.Ordered](t1 *testing.T, t *Tree[V], n *node[V]) Hash {
+	if n == t.nilNode {
+		return Hash{}
+	}
+
+	want := addHash(addHash(checkMerkle(t1, t, n.left), t.elementHash(n.element.key, n.element.value)), checkMerkle(t1, t, n.right))
+	if n.ext.merkle.sum != want {
+		t1.Fatalf("node %v has merkle hash %x, want %x", n.element.key, n.ext.merkle.sum, want)
+	}
+
+	return want
+}
@@ -0,0 +1,56 @@
+package rbtree
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Nearest is a function for searching key of tree with the minimum distance |key - x| in O(log n).
+// If two keys have the same distance, the smaller key is returned. Error is returned if tree is empty.
+// Distances should fit into type of keys (e.g. no overflow of `int8`).
+// - param t is tree with numeric keys (`int`, `uint`, `float` etc)
+// - param x is the point of search
+func Nearest[V Number](t *Tree[V], x V) (V, error) {
+	keys := KNearest(t, x, 1)
+	if len(keys) == 0 {
+		var result V
+		return result, errors.New(fmt.Sprintf("tree has no key near %v", x))
+	}
+
+	return keys[0], nil
+}
+
+// KNearest is a function for searching k keys of tree with the minimum distances |key - x| in O(log n + k).
+// Keys are returned in order of distance, if two keys have the same distance, the smaller key is the first.
+// Search walks outward from the greatest key <= x and the smallest key > x.
+// Distances should fit into type of keys (e.g. no overflow of `int8`).
+// - param t is tree with numeric keys (`int`, `uint`, `float` etc)
+// - param x is the point of search
+// - param k is the max count of keys
+func KNearest[V Number](t *Tree[V], x V, k int) []V {
+	t.guard.startRead()
+	defer t.guard.endRead()
+
+	var keys []V
+	lo, hi := t.floor(x, false), t.ceiling(x, true)
+	for len(keys) < k && (lo != nil || hi != nil) {
+		if hi == nil || lo != nil && distance(lo.element.key, x) <= distance(hi.element.key, x) {
+			keys = append(keys, lo.element.key)
+			lo = t.predecessor(lo)
+			continue
+		}
+		keys = append(keys, hi.element.key)
+		hi = t.successor(hi)
+	}
+
+	return keys
+}
+
+// distance - internal function for getting |a - b| without negative differences of unsigned numbers
+func distance[V Number](a, b V) V {
+	if a >= b {
+		return a - b
+	}
+
+	return b - a
+}
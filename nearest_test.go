@@ -0,0 +1,81 @@
+package rbtree
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestKNearest(t1 *testing.T) {
+	t := New[uint]()
+	keys := map[uint]bool{}
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 500; i++ {
+		key := uint(rng.Intn(1000))
+		t.Insert(key, i)
+		keys[key] = true
+
+		x, k := uint(rng.Intn(1100)), rng.Intn(10)
+		want := bruteNearest(keys, x, k)
+		if got := KNearest(t, x, k); !reflect.DeepEqual(got, want) {
+			t1.Fatalf("KNearest(%v, %v) = %v, want %v", x, k, got, want)
+		}
+		if got, err := Nearest(t, x); err != nil || got != bruteNearest(keys, x, 1)[0] {
+			t1.Fatalf("Nearest(%v) = %v, %v, want %v", x, got, err, bruteNearest(keys, x, 1)[0])
+		}
+	}
+}
+
+func TestNearest(t1 *testing.T) {
+	t := New[float64]()
+	for _, key := range []float64{-2.5, 1, 3, 10} {
+		t.Insert(key, key)
+	}
+
+	tests := []struct {
+		name string
+		x    float64
+		k    int
+		want []float64
+	}{
+		{name: "existing key", x: 3, k: 2, want: []float64{3, 1}},
+		{name: "tie goes to smaller key", x: 2, k: 2, want: []float64{1, 3}},
+		{name: "before min", x: -10, k: 1, want: []float64{-2.5}},
+		{name: "after max", x: 100, k: 2, want: []float64{10, 3}},
+		{name: "k greater than len", x: 0, k: 10, want: []float64{1, -2.5, 3, 10}},
+		{name: "zero k", x: 0, k: 0, want: nil},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			if got := KNearest(t, tt.x, tt.k); !reflect.DeepEqual(got, tt.want) {
+				t1.Errorf("KNearest(%v, %v) = %v, want %v", tt.x, tt.k, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Nearest(New[int](), 0); err == nil {
+		t1.Errorf("Nearest() of empty tree error = nil")
+	}
+}
+
+// bruteNearest returns k nearest keys to x by sorting of all keys
+func bruteNearest(keys map[uint]bool, x uint, k int) []uint {
+	var result []uint
+	for key := range keys {
+		result = append(result, key)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		di, dj := distance(result[i], x), distance(result[j], x)
+		return di < dj || di == dj && result[i] < result[j]
+	})
+	if k < len(result) {
+		result = result[:k]
+	}
+	if len(result) == 0 {
+		return nil
+	}
+
+	return result
+}
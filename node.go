@@ -18,6 +18,14 @@ type node[V constraints.Ordered] struct {
 	left    *node[V]
 	right   *node[V]
 	color   color
+	ext     *nodeExt // nil if tree has neither merkle hashes nor augmentation
+}
+
+// nodeExt is the structure of node's optional data, it's allocated only for trees which use it,
+// so nodes of plain tree grow by one pointer
+type nodeExt struct {
+	merkle merkleNode // hashes of element and subtree if tree has merkle hashes
+	agg    any        // data of tree's augmentation (aggregate of subtree etc)
 }
 
 type element[V constraints.Ordered] struct {
@@ -25,16 +33,21 @@ type element[V constraints.Ordered] struct {
 	value any
 }
 
-func search[V constraints.Ordered](n *node[V], key V) *node[V] {
-	for n != nil && key != n.element.key {
-		if key < n.element.key {
-			n = n.left
-			continue
-		}
-		n = n.right
+// agg - internal function for getting data of tree's augmentation, it's nil for nilNode
+func (n *node[V]) agg() any {
+	if n.ext == nil {
+		return nil
 	}
 
-	return n
+	return n.ext.agg
+}
+
+// setAgg - internal function for setting data of tree's augmentation
+func (n *node[V]) setAgg(a any) {
+	if n.ext == nil {
+		n.ext = &nodeExt{}
+	}
+	n.ext.agg = a
 }
 
 func isRed[V constraints.Ordered](n *node[V]) bool {
@@ -0,0 +1,300 @@
+package rbtree
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Number is a constraint of NumericTree's values
+type Number interface {
+	constraints.Integer | constraints.Float
+}
+
+// NumericTree is a tree with numeric values which supports adding delta to all values of key range
+// and range sum, min and max queries in O(log n).
+// AddRange changes only O(log n) nodes: delta of whole subtree is kept in subtree's root (lazy tag)
+// and is moved to children when tree is changed under this node
+type NumericTree[V constraints.Ordered, N Number] struct {
+	tree *Tree[V] // values are N
+}
+
+// numericNode is the structure of NumericTree's node augmentation.
+// All fields include tags of the node itself, but not tags of node's ancestors.
+// tag is delta which isn't added to values of node's children yet
+type numericNode[N Number] struct {
+	sum, min, max N
+	count         int
+	tag           N
+}
+
+// numeric is the augmentation of NumericTree
+type numeric[V constraints.Ordered, N Number] struct{}
+
+// NewNumericTree is a function for creation empty tree with numeric values
+// - param should be `ordered type` (`int`, `string`, `float` etc)
+func NewNumericTree[V constraints.Ordered, N Number]() *NumericTree[V, N] {
+	t := New[V]()
+	t.aug = numeric[V, N]{}
+
+	return &NumericTree[V, N]{tree: t}
+}
+
+// Insert is a function for inserting element into tree.
+// If element with the same key exists, its value is replaced.
+// - param key should be `ordered type` (`int`, `string`, `float` etc.)
+func (t *NumericTree[V, N]) Insert(key V, value N) {
+	t.tree.Insert(key, value)
+}
+
+// Delete is a function for deleting element from tree
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *NumericTree[V, N]) Delete(key V) {
+	t.tree.Delete(key)
+}
+
+// Exists is a function for searching element in tree. If element exists in tree - return true, else - false
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *NumericTree[V, N]) Exists(key V) bool {
+	return t.tree.Exists(key)
+}
+
+// GetValue is a function for searching element in tree and returning value of this element
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *NumericTree[V, N]) GetValue(key V) (N, error) {
+	t.tree.guard.startRead()
+	defer t.tree.guard.endRead()
+
+	// tags of ancestors are added on the fly: reads don't change tree
+	var delta N
+	n := t.tree.root
+	for n != t.tree.nilNode && key != n.element.key {
+		delta += numericOf[N](n).tag
+		if key < n.element.key {
+			n = n.left
+			continue
+		}
+		n = n.right
+	}
+
+	if n == t.tree.nilNode {
+		return 0, errors.New(fmt.Sprintf("element with key %v not found", key))
+	}
+
+	return n.element.value.(N) + delta, nil
+}
+
+// Len is a function for getting count of elements in tree.
+func (t *NumericTree[V, N]) Len() int {
+	return t.tree.Len()
+}
+
+// Ascend is a function for iterating over tree's elements in key order.
+// Iteration stops when fn returns false.
+func (t *NumericTree[V, N]) Ascend(fn func(key V, value N) bool) {
+	t.tree.guard.startRead()
+	defer t.tree.guard.endRead()
+
+	t.ascend(t.tree.root, 0, fn)
+}
+
+// AddRange is a function for adding delta to values of all elements with keys in range [lo, hi) in O(log n).
+// - params lo and hi should be `ordered type` (`int`, `string`, `float` etc)
+func (t *NumericTree[V, N]) AddRange(lo, hi V, delta N) {
+	t.tree.guard.startWrite()
+	defer t.tree.guard.endWrite()
+
+	t.addRange(t.tree.root, lo, hi, delta, false, false)
+}
+
+// RangeSum is a function for getting sum of values of elements with keys in range [lo, hi) in O(log n).
+// - params lo and hi should be `ordered type` (`int`, `string`, `float` etc)
+func (t *NumericTree[V, N]) RangeSum(lo, hi V) N {
+	return t.rangeOf(lo, hi).sum
+}
+
+// RangeMin is a function for getting min value of elements with keys in range [lo, hi) in O(log n).
+// It returns false if range is empty.
+// - params lo and hi should be `ordered type` (`int`, `string`, `float` etc)
+func (t *NumericTree[V, N]) RangeMin(lo, hi V) (N, bool) {
+	result := t.rangeOf(lo, hi)
+
+	return result.min, result.count > 0
+}
+
+// RangeMax is a function for getting max value of elements with keys in range [lo, hi) in O(log n).
+// It returns false if range is empty.
+// - params lo and hi should be `ordered type` (`int`, `string`, `float` etc)
+func (t *NumericTree[V, N]) RangeMax(lo, hi V) (N, bool) {
+	result := t.rangeOf(lo, hi)
+
+	return result.max, result.count > 0
+}
+
+// ascend - internal function for iterating over subtree n, delta is sum of tags of n's ancestors.
+// It returns false if fn stopped iteration.
+func (t *NumericTree[V, N]) ascend(n *node[V], delta N, fn func(key V, value N) bool) bool {
+	if n == t.tree.nilNode {
+		return true
+	}
+
+	childDelta := delta + numericOf[N](n).tag
+
+	return t.ascend(n.left, childDelta, fn) &&
+		fn(n.element.key, n.element.value.(N)+delta) &&
+		t.ascend(n.right, childDelta, fn)
+}
+
+// addRange - internal function for adding delta to elements of subtree n with keys in range [lo, hi).
+// loDone (hiDone) is true if all keys of subtree are known to be >= lo (< hi).
+func (t *NumericTree[V, N]) addRange(n *node[V], lo, hi V, delta N, loDone, hiDone bool) {
+	if n == t.tree.nilNode {
+		return
+	}
+
+	switch {
+	case loDone && hiDone:
+		applyDelta(n, delta)
+		return
+	case !loDone && n.element.key < lo:
+		t.addRange(n.right, lo, hi, delta, loDone, hiDone)
+	case !hiDone && n.element.key >= hi:
+		t.addRange(n.left, lo, hi, delta, loDone, hiDone)
+	default:
+		n.element.value = n.element.value.(N) + delta
+		t.addRange(n.left, lo, hi, delta, loDone, true)
+		t.addRange(n.right, lo, hi, delta, true, hiDone)
+	}
+	t.tree.pull(n)
+}
+
+// rangeOf - internal function for getting augmentation of elements with keys in range [lo, hi)
+func (t *NumericTree[V, N]) rangeOf(lo, hi V) numericNode[N] {
+	t.tree.guard.startRead()
+	defer t.tree.guard.endRead()
+
+	// the highest node in range divides it into suffix of its left subtree and prefix of its right subtree
+	var delta N
+	n := t.tree.root
+	for n != t.tree.nilNode && (n.element.key < lo || n.element.key >= hi) {
+		delta += numericOf[N](n).tag
+		if n.element.key < lo {
+			n = n.right
+			continue
+		}
+		n = n.left
+	}
+	if n == t.tree.nilNode {
+		return numericNode[N]{}
+	}
+
+	childDelta := delta + numericOf[N](n).tag
+	return combineNumeric(t.from(n.left, lo, childDelta), n.element.value.(N)+delta, t.before(n.right, hi, childDelta))
+}
+
+// from - internal function for getting augmentation of elements of subtree n with keys >= lo,
+// delta is sum of tags of n's ancestors
+func (t *NumericTree[V, N]) from(n *node[V], lo V, delta N) numericNode[N] {
+	for n != t.tree.nilNode && n.element.key < lo {
+		delta += numericOf[N](n).tag
+		n = n.right
+	}
+	if n == t.tree.nilNode {
+		return numericNode[N]{}
+	}
+
+	childDelta := delta + numericOf[N](n).tag
+	return combineNumeric(t.from(n.left, lo, childDelta), n.element.value.(N)+delta, shiftNumeric(*numericOf[N](n.right), childDelta))
+}
+
+// before - internal function for getting augmentation of elements of subtree n with keys < hi,
+// delta is sum of tags of n's ancestors
+func (t *NumericTree[V, N]) before(n *node[V], hi V, delta N) numericNode[N] {
+	for n != t.tree.nilNode && n.element.key >= hi {
+		delta += numericOf[N](n).tag
+		n = n.left
+	}
+	if n == t.tree.nilNode {
+		return numericNode[N]{}
+	}
+
+	childDelta := delta + numericOf[N](n).tag
+	return combineNumeric(shiftNumeric(*numericOf[N](n.left), childDelta), n.element.value.(N)+delta, t.before(n.right, hi, childDelta))
+}
+
+func (numeric[V, N]) pull(n *node[V]) {
+	a, ok := n.agg().(*numericNode[N])
+	if !ok {
+		a = &numericNode[N]{}
+		n.setAgg(a)
+	}
+
+	result := combineNumeric(shiftNumeric(*numericOf[N](n.left), a.tag), n.element.value.(N), shiftNumeric(*numericOf[N](n.right), a.tag))
+	result.tag = a.tag
+	*a = result
+}
+
+func (numeric[V, N]) push(n *node[V]) {
+	a := numericOf[N](n)
+	if a.tag == 0 {
+		return
+	}
+
+	for _, child := range []*node[V]{n.left, n.right} {
+		if child.agg() != nil {
+			applyDelta(child, a.tag)
+		}
+	}
+	a.tag = 0
+}
+
+// numericOf - internal function for getting augmentation of node, augmentation of nilNode is empty
+func numericOf[N Number, V constraints.Ordered](n *node[V]) *numericNode[N] {
+	if a, ok := n.agg().(*numericNode[N]); ok {
+		return a
+	}
+
+	return &numericNode[N]{}
+}
+
+// applyDelta - internal function for adding delta to all values of subtree n
+func applyDelta[N Number, V constraints.Ordered](n *node[V], delta N) {
+	a := numericOf[N](n)
+	n.element.value = n.element.value.(N) + delta
+	*a = shiftNumeric(*a, delta)
+	a.tag += delta
+}
+
+// shiftNumeric - internal function for getting augmentation of subtree after adding delta to all its values
+func shiftNumeric[N Number](a numericNode[N], delta N) numericNode[N] {
+	if a.count == 0 {
+		return a
+	}
+
+	a.sum += delta * N(a.count)
+	a.min += delta
+	a.max += delta
+
+	return a
+}
+
+// combineNumeric - internal function for getting augmentation of subtree from augmentations of children and value
+func combineNumeric[N Number](left numericNode[N], value N, right numericNode[N]) numericNode[N] {
+	result := numericNode[N]{sum: value, min: value, max: value, count: 1}
+	for _, a := range []numericNode[N]{left, right} {
+		if a.count == 0 {
+			continue
+		}
+		result.sum += a.sum
+		result.count += a.count
+		if a.min < result.min {
+			result.min = a.min
+		}
+		if a.max > result.max {
+			result.max = a.max
+		}
+	}
+
+	return result
+}
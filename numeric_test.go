@@ -0,0 +1,127 @@
+package rbtree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestNumericTree_AddRange(t1 *testing.T) {
+	t := NewNumericTree[int, int]()
+	want := map[int]int{}
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 3000; i++ {
+		key := rng.Intn(300)
+		lo := rng.Intn(320) - 10
+		hi := lo + rng.Intn(100)
+		switch rng.Intn(4) {
+		case 0:
+			t.Delete(key)
+			delete(want, key)
+		case 1:
+			delta := rng.Intn(21) - 10
+			t.AddRange(lo, hi, delta)
+			for key := range want {
+				if key >= lo && key < hi {
+					want[key] += delta
+				}
+			}
+		default:
+			value := rng.Intn(100)
+			t.Insert(key, value)
+			want[key] = value
+		}
+		checkTreeProperties(t1, t.tree)
+		checkNumeric(t1, t.tree, t.tree.root)
+
+		wantSum, wantMin, wantMax, count := 0, 0, 0, 0
+		for key, value := range want {
+			if key < lo || key >= hi {
+				continue
+			}
+			if count == 0 || value < wantMin {
+				wantMin = value
+			}
+			if count == 0 || value > wantMax {
+				wantMax = value
+			}
+			wantSum += value
+			count++
+		}
+		if got := t.RangeSum(lo, hi); got != wantSum {
+			t1.Fatalf("RangeSum(%v, %v) = %v, want %v", lo, hi, got, wantSum)
+		}
+		if got, ok := t.RangeMin(lo, hi); ok != (count > 0) || ok && got != wantMin {
+			t1.Fatalf("RangeMin(%v, %v) = %v, %v, want %v", lo, hi, got, ok, wantMin)
+		}
+		if got, ok := t.RangeMax(lo, hi); ok != (count > 0) || ok && got != wantMax {
+			t1.Fatalf("RangeMax(%v, %v) = %v, %v, want %v", lo, hi, got, ok, wantMax)
+		}
+	}
+
+	for key, value := range want {
+		if got, err := t.GetValue(key); err != nil || got != value {
+			t1.Errorf("GetValue(%v) = %v, %v, want %v", key, got, err, value)
+		}
+	}
+	count := 0
+	t.Ascend(func(key int, value int) bool {
+		if want[key] != value {
+			t1.Errorf("Ascend() value of %v = %v, want %v", key, value, want[key])
+		}
+		count++
+		return true
+	})
+	if count != len(want) || t.Len() != len(want) {
+		t1.Errorf("Ascend() visited %v elements, Len() = %v, want %v", count, t.Len(), len(want))
+	}
+}
+
+func TestNumericTree_float(t1 *testing.T) {
+	t := NewNumericTree[string, float64]()
+	t.Insert("apple", 1.5)
+	t.Insert("banana", 2)
+	t.Insert("cherry", 4)
+	t.AddRange("b", "z", 0.5)
+	t.AddRange("a", "c", -1)
+
+	tests := []struct {
+		name string
+		key  string
+		want float64
+	}{
+		{name: "first range only", key: "apple", want: 0.5},
+		{name: "both ranges", key: "banana", want: 1.5},
+		{name: "second range only", key: "cherry", want: 4.5},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			if got, err := t.GetValue(tt.key); err != nil || got != tt.want {
+				t1.Errorf("GetValue(%v) = %v, %v, want %v", tt.key, got, err, tt.want)
+			}
+		})
+	}
+	if _, err := t.GetValue("date"); err == nil {
+		t1.Errorf("GetValue() of missing key error = nil")
+	}
+	if got := t.RangeSum("a", "z"); got != 6.5 {
+		t1.Errorf("RangeSum() = %v, want 6.5", got)
+	}
+}
+
+// checkNumeric checks augmentation of all nodes of subtree n and returns augmentation of subtree
+func checkNumeric(t1 *testing.T, t *Tree[int], n *node[int]) numericNode[int] {
+	if n == t.nilNode {
+		return numericNode[int]{}
+	}
+
+	a := numericOf[int](n)
+	left := shiftNumeric(checkNumeric(t1, t, n.left), a.tag)
+	right := shiftNumeric(checkNumeric(t1, t, n.right), a.tag)
+	want := combineNumeric(left, n.element.value.(int), right)
+	if want.sum != a.sum || want.min != a.min || want.max != a.max || want.count != a.count {
+		t1.Fatalf("node %v has augmentation %v, want %v", n.element.key, *a, want)
+	}
+
+	return want
+}
@@ -0,0 +1,36 @@
+package rbtree
+
+// Option is a function for configuring tree in New and NewWithElement
+type Option func(o *options)
+
+type options struct {
+	checkAccess bool
+	merkle      bool
+}
+
+// WithAccessCheck is an option for detecting concurrent misuse of tree.
+// Tree panics with clear message when writer overlaps with other writer or reader,
+// instead of silently corrupting its nodes.
+// Check can be enabled for all trees by build tag `rbtreecheck`.
+func WithAccessCheck() Option {
+	return func(o *options) {
+		o.checkAccess = true
+	}
+}
+
+// WithMerkle is an option for keeping hash of content of every subtree (see RootHash and MerkleDiff).
+// Elements are hashed in binary encoding of tree's codecs, so values should have deterministic encoding.
+func WithMerkle() Option {
+	return func(o *options) {
+		o.merkle = true
+	}
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
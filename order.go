@@ -0,0 +1,194 @@
+package rbtree
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"golang.org/x/exp/constraints"
+)
+
+// OrderTree is a tree which keeps size and total weight of every subtree (order-statistic tree),
+// so elements can be found by position, ranked and sampled at random in O(log n).
+// All methods of Tree can be used
+type OrderTree[V constraints.Ordered] struct {
+	*Tree[V]
+	order *order[V]
+}
+
+// orderNode is the structure of OrderTree's node augmentation
+type orderNode struct {
+	size   int
+	weight float64
+}
+
+// order is the augmentation of OrderTree
+type order[V constraints.Ordered] struct {
+	weight func(key V, value any) float64
+}
+
+// NewOrderTree is a function for creation empty tree with subtree sizes and weights
+// - param weight returns non-negative weight of element for WeightedSample, nil means weight 1 for all elements
+// - param opts are optional settings of tree (WithAccessCheck etc)
+func NewOrderTree[V constraints.Ordered](weight func(key V, value any) float64, opts ...Option) *OrderTree[V] {
+	if weight == nil {
+		weight = func(key V, value any) float64 { return 1 }
+	}
+	o := &order[V]{weight: weight}
+	t := New[V](opts...)
+	t.aug = o
+
+	return &OrderTree[V]{Tree: t, order: o}
+}
+
+// Sample is a function for getting uniformly random element of tree in O(log n).
+// It returns false if tree is empty.
+// - param rng is source of random numbers
+func (t *OrderTree[V]) Sample(rng *rand.Rand) (V, any, bool) {
+	t.guard.startRead()
+	defer t.guard.endRead()
+
+	return t.elementAt(rng, 0, t.size)
+}
+
+// SampleRange is a function for getting uniformly random element with key in range [lo, hi) in O(log n).
+// It returns false if there are no such elements.
+// - params lo and hi should be `ordered type` (`int`, `string`, `float` etc)
+// - param rng is source of random numbers
+func (t *OrderTree[V]) SampleRange(lo, hi V, rng *rand.Rand) (V, any, bool) {
+	t.guard.startRead()
+	defer t.guard.endRead()
+
+	return t.elementAt(rng, t.rank(lo), t.rank(hi))
+}
+
+// WeightedSample is a function for getting random element of tree with probability proportional to its weight
+// in O(log n). It returns false if total weight of elements is 0.
+// - param rng is source of random numbers
+func (t *OrderTree[V]) WeightedSample(rng *rand.Rand) (V, any, bool) {
+	t.guard.startRead()
+	defer t.guard.endRead()
+
+	var key V
+	total := orderOf(t.root).weight
+	if total <= 0 {
+		return key, nil, false
+	}
+
+	// subtree of n always has positive weight: rounding of r can't lead to element with zero weight
+	r := rng.Float64() * total
+	n := t.root
+	for {
+		left, right := orderOf(n.left).weight, orderOf(n.right).weight
+		self := t.order.weight(n.element.key, n.element.value)
+		switch {
+		case r < left || self <= 0 && right <= 0:
+			n = n.left
+		case r < left+self || right <= 0:
+			return n.element.key, n.element.value, true
+		default:
+			r -= left + self
+			n = n.right
+		}
+	}
+}
+
+// Select is a function for getting element at position i in key order in O(log n)
+// - param i should be in range [0, Len())
+func (t *OrderTree[V]) Select(i int) (V, any, error) {
+	t.guard.startRead()
+	defer t.guard.endRead()
+
+	var key V
+	if i < 0 || i >= t.size {
+		return key, nil, errors.New(fmt.Sprintf("index %v out of range [0, %v)", i, t.size))
+	}
+	n := t.selectNode(i)
+
+	return n.element.key, n.element.value, nil
+}
+
+// Rank is a function for getting count of elements with keys < key in O(log n),
+// it's position of element with key if element exists
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *OrderTree[V]) Rank(key V) int {
+	t.guard.startRead()
+	defer t.guard.endRead()
+
+	return t.rank(key)
+}
+
+// Split is a function for moving elements with keys >= key to new tree with the same weights (see Tree.Split).
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *OrderTree[V]) Split(key V) *OrderTree[V] {
+	return &OrderTree[V]{Tree: t.Tree.Split(key), order: t.order}
+}
+
+// elementAt - internal function for getting uniformly random element at position in range [from, to)
+func (t *OrderTree[V]) elementAt(rng *rand.Rand, from, to int) (V, any, bool) {
+	var key V
+	if from >= to {
+		return key, nil, false
+	}
+
+	n := t.selectNode(from + rng.Intn(to-from))
+
+	return n.element.key, n.element.value, true
+}
+
+// selectNode - internal function for searching node at position i in key order, i should be in range [0, Len())
+func (t *OrderTree[V]) selectNode(i int) *node[V] {
+	n := t.root
+	for {
+		left := orderOf(n.left).size
+		switch {
+		case i < left:
+			n = n.left
+		case i == left:
+			return n
+		default:
+			i -= left + 1
+			n = n.right
+		}
+	}
+}
+
+// rank - internal function for getting count of elements with keys < key
+func (t *OrderTree[V]) rank(key V) int {
+	rank := 0
+	n := t.root
+	for n != t.nilNode {
+		if key <= n.element.key {
+			n = n.left
+			continue
+		}
+		rank += orderOf(n.left).size + 1
+		n = n.right
+	}
+
+	return rank
+}
+
+func (o *order[V]) pull(n *node[V]) {
+	p, ok := n.agg().(*orderNode)
+	if !ok {
+		p = &orderNode{}
+		n.setAgg(p)
+	}
+	left, right := orderOf(n.left), orderOf(n.right)
+	*p = orderNode{
+		size:   left.size + 1 + right.size,
+		weight: left.weight + o.weight(n.element.key, n.element.value) + right.weight,
+	}
+}
+
+func (o *order[V]) push(n *node[V]) {}
+
+// orderOf - internal function for getting augmentation of node, augmentation of nilNode is empty
+func orderOf[V constraints.Ordered](n *node[V]) orderNode {
+	if p, ok := n.agg().(*orderNode); ok {
+		return *p
+	}
+
+	return orderNode{}
+}
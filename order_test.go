@@ -0,0 +1,149 @@
+package rbtree
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestOrderTree_Sample(t1 *testing.T) {
+	t := NewOrderTree[int](func(key int, value any) float64 { return float64(value.(int)) })
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		key := rng.Intn(300)
+		if rng.Intn(3) == 0 {
+			t.Delete(key)
+		} else {
+			t.Insert(key, rng.Intn(5))
+		}
+		checkOrder(t1, t, t.root)
+	}
+
+	right := t.Split(150)
+	checkOrder(t1, t, t.root)
+	checkOrder(t1, right, right.root)
+	mustNoErr(t1, t.Join(right.Tree))
+	checkOrder(t1, t, t.root)
+}
+
+func TestOrderTree_distribution(t1 *testing.T) {
+	// weight of key is key, so key 0 is never sampled by WeightedSample
+	t := NewOrderTree[int](func(key int, value any) float64 { return float64(key) })
+	for key := 0; key < 10; key++ {
+		t.Insert(key, key*10)
+	}
+
+	const draws = 100000
+	rng := rand.New(rand.NewSource(1))
+	tests := []struct {
+		name   string
+		sample func() (int, any, bool)
+		want   func(key int) float64
+	}{
+		{
+			name:   "Sample",
+			sample: func() (int, any, bool) { return t.Sample(rng) },
+			want:   func(key int) float64 { return 0.1 },
+		},
+		{
+			name:   "WeightedSample",
+			sample: func() (int, any, bool) { return t.WeightedSample(rng) },
+			want:   func(key int) float64 { return float64(key) / 45 },
+		},
+		{
+			name:   "SampleRange",
+			sample: func() (int, any, bool) { return t.SampleRange(3, 7, rng) },
+			want: func(key int) float64 {
+				if key >= 3 && key < 7 {
+					return 0.25
+				}
+				return 0
+			},
+		},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			counts := map[int]int{}
+			for i := 0; i < draws; i++ {
+				key, value, ok := tt.sample()
+				if !ok || value != key*10 {
+					t1.Fatalf("sample = %v, %v, %v", key, value, ok)
+				}
+				counts[key]++
+			}
+			for key := 0; key < 10; key++ {
+				got, want := float64(counts[key])/draws, tt.want(key)
+				if want == 0 && got != 0 || math.Abs(got-want) > 0.01 {
+					t1.Errorf("key %v has frequency %v, want %v", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestOrderTree_Sample_empty(t1 *testing.T) {
+	t := NewOrderTree[int](func(key int, value any) float64 { return 0 })
+	rng := rand.New(rand.NewSource(1))
+	if _, _, ok := t.Sample(rng); ok {
+		t1.Errorf("Sample() of empty tree ok = true")
+	}
+
+	t.Insert(1, 1)
+	if _, _, ok := t.WeightedSample(rng); ok {
+		t1.Errorf("WeightedSample() with zero weights ok = true")
+	}
+	if _, _, ok := t.SampleRange(2, 10, rng); ok {
+		t1.Errorf("SampleRange() of empty range ok = true")
+	}
+	if _, _, ok := t.SampleRange(10, 0, rng); ok {
+		t1.Errorf("SampleRange() of inverted range ok = true")
+	}
+}
+
+func TestOrderTree_SelectRank(t1 *testing.T) {
+	t := NewOrderTree[int](nil)
+	want := map[int]int{}
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 1000; i++ {
+		key := rng.Intn(300)
+		if rng.Intn(3) == 0 {
+			t.Delete(key)
+			delete(want, key)
+		} else {
+			t.Insert(key, i)
+			want[key] = i
+		}
+
+		keys := sortedKeys(want)
+		pos := rng.Intn(len(keys) + 1)
+		if pos < len(keys) {
+			if key, value, err := t.Select(pos); err != nil || key != keys[pos] || value != want[key] {
+				t1.Fatalf("Select(%v) = %v, %v, %v, want %v", pos, key, value, err, keys[pos])
+			}
+		} else if _, _, err := t.Select(pos); err == nil {
+			t1.Fatalf("Select(%v) of %v elements error = nil", pos, len(keys))
+		}
+
+		key = rng.Intn(320) - 10
+		if got, want := t.Rank(key), sort.SearchInts(keys, key); got != want {
+			t1.Fatalf("Rank(%v) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+// checkOrder checks sizes and weights of all nodes of subtree n and returns augmentation of subtree
+func checkOrder(t1 *testing.T, t *OrderTree[int], n *node[int]) orderNode {
+	if n == t.nilNode {
+		return orderNode{}
+	}
+
+	left, right := checkOrder(t1, t, n.left), checkOrder(t1, t, n.right)
+	want := orderNode{size: left.size + 1 + right.size, weight: left.weight + float64(n.element.value.(int)) + right.weight}
+	if got := orderOf(n); got != want {
+		t1.Fatalf("node %v has augmentation %v, want %v", n.element.key, got, want)
+	}
+
+	return want
+}
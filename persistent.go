@@ -0,0 +1,239 @@
+package rbtree
+
+import "golang.org/x/exp/constraints"
+
+// pnode is the structure of persistent tree's node.
+// pnode is never changed after creation: every modification
+// copies the path from the root to the changed node, so old roots
+// stay valid and can be read while new versions are being built
+type pnode[V constraints.Ordered] struct {
+	element element[V]
+	left    *pnode[V]
+	right   *pnode[V]
+	color   color
+	size    int // count of elements in subtree
+}
+
+func newPNode[V constraints.Ordered](c color, left *pnode[V], e element[V], right *pnode[V]) *pnode[V] {
+	return &pnode[V]{
+		element: e,
+		left:    left,
+		right:   right,
+		color:   c,
+		size:    pSize(left) + 1 + pSize(right),
+	}
+}
+
+func pSize[V constraints.Ordered](n *pnode[V]) int {
+	if n == nil {
+		return 0
+	}
+
+	return n.size
+}
+
+func pSearch[V constraints.Ordered](n *pnode[V], key V) *pnode[V] {
+	for n != nil && key != n.element.key {
+		if key < n.element.key {
+			n = n.left
+			continue
+		}
+		n = n.right
+	}
+
+	return n
+}
+
+func pIsRed[V constraints.Ordered](n *pnode[V]) bool {
+	return n != nil && n.color == red
+}
+
+func pIsBlack[V constraints.Ordered](n *pnode[V]) bool {
+	return n != nil && n.color == black
+}
+
+func pWithColor[V constraints.Ordered](n *pnode[V], c color) *pnode[V] {
+	if n == nil || n.color == c {
+		return n
+	}
+
+	return newPNode(c, n.left, n.element, n.right)
+}
+
+// pInsert returns new root of persistent tree with element e.
+// If element with the same key exists, its value is replaced.
+func pInsert[V constraints.Ordered](n *pnode[V], e element[V]) *pnode[V] {
+	return pWithColor(pIns(n, e), black)
+}
+
+func pIns[V constraints.Ordered](n *pnode[V], e element[V]) *pnode[V] {
+	if n == nil {
+		return newPNode(red, nil, e, nil)
+	}
+
+	switch {
+	case e.key < n.element.key:
+		if n.color == black {
+			return pBalance(pIns(n.left, e), n.element, n.right)
+		}
+		return newPNode(red, pIns(n.left, e), n.element, n.right)
+	case e.key > n.element.key:
+		if n.color == black {
+			return pBalance(n.left, n.element, pIns(n.right, e))
+		}
+		return newPNode(red, n.left, n.element, pIns(n.right, e))
+	}
+
+	return newPNode(n.color, n.left, e, n.right)
+}
+
+// pDelete returns new root of persistent tree without element with key.
+// Element with key must exist in tree.
+func pDelete[V constraints.Ordered](n *pnode[V], key V) *pnode[V] {
+	return pWithColor(pDel(n, key), black)
+}
+
+func pDel[V constraints.Ordered](n *pnode[V], key V) *pnode[V] {
+	if n == nil {
+		return nil
+	}
+
+	switch {
+	case key < n.element.key:
+		if pIsBlack(n.left) {
+			return pBalanceLeft(pDel(n.left, key), n.element, n.right)
+		}
+		return newPNode(red, pDel(n.left, key), n.element, n.right)
+	case key > n.element.key:
+		if pIsBlack(n.right) {
+			return pBalanceRight(n.left, n.element, pDel(n.right, key))
+		}
+		return newPNode(red, n.left, n.element, pDel(n.right, key))
+	}
+
+	return pAppend(n.left, n.right)
+}
+
+// pBalance - internal function for recovery of rbtree's properties
+// when one of the children has two red nodes in a row
+func pBalance[V constraints.Ordered](l *pnode[V], e element[V], r *pnode[V]) *pnode[V] {
+	switch {
+	case pIsRed(l) && pIsRed(r):
+		return newPNode(red, pWithColor(l, black), e, pWithColor(r, black))
+	case pIsRed(l) && pIsRed(l.left):
+		return newPNode(red,
+			pWithColor(l.left, black),
+			l.element,
+			newPNode(black, l.right, e, r),
+		)
+	case pIsRed(l) && pIsRed(l.right):
+		return newPNode(red,
+			newPNode(black, l.left, l.element, l.right.left),
+			l.right.element,
+			newPNode(black, l.right.right, e, r),
+		)
+	case pIsRed(r) && pIsRed(r.right):
+		return newPNode(red,
+			newPNode(black, l, e, r.left),
+			r.element,
+			pWithColor(r.right, black),
+		)
+	case pIsRed(r) && pIsRed(r.left):
+		return newPNode(red,
+			newPNode(black, l, e, r.left.left),
+			r.left.element,
+			newPNode(black, r.left.right, r.element, r.right),
+		)
+	}
+
+	return newPNode(black, l, e, r)
+}
+
+// pBalanceLeft - internal function for recovery of rbtree's properties
+// when left subtree has lost one black node after deleting
+func pBalanceLeft[V constraints.Ordered](l *pnode[V], e element[V], r *pnode[V]) *pnode[V] {
+	switch {
+	case pIsRed(l):
+		return newPNode(red, pWithColor(l, black), e, r)
+	case pIsBlack(r):
+		return pBalance(l, e, pWithColor(r, red))
+	case pIsRed(r) && pIsBlack(r.left):
+		return newPNode(red,
+			newPNode(black, l, e, r.left.left),
+			r.left.element,
+			pBalance(r.left.right, r.element, pWithColor(r.right, red)),
+		)
+	}
+
+	panic("rbtree: persistent tree's properties are broken")
+}
+
+// pBalanceRight - internal function for recovery of rbtree's properties
+// when right subtree has lost one black node after deleting
+func pBalanceRight[V constraints.Ordered](l *pnode[V], e element[V], r *pnode[V]) *pnode[V] {
+	switch {
+	case pIsRed(r):
+		return newPNode(red, l, e, pWithColor(r, black))
+	case pIsBlack(l):
+		return pBalance(pWithColor(l, red), e, r)
+	case pIsRed(l) && pIsBlack(l.right):
+		return newPNode(red,
+			pBalance(pWithColor(l.left, red), l.element, l.right.left),
+			l.right.element,
+			newPNode(black, l.right.right, e, r),
+		)
+	}
+
+	panic("rbtree: persistent tree's properties are broken")
+}
+
+// pAppend - internal function for joining two subtrees of deleted node
+func pAppend[V constraints.Ordered](l, r *pnode[V]) *pnode[V] {
+	switch {
+	case l == nil:
+		return r
+	case r == nil:
+		return l
+	case pIsRed(l) && pIsRed(r):
+		m := pAppend(l.right, r.left)
+		if pIsRed(m) {
+			return newPNode(red,
+				newPNode(red, l.left, l.element, m.left),
+				m.element,
+				newPNode(red, m.right, r.element, r.right),
+			)
+		}
+		return newPNode(red, l.left, l.element, newPNode(red, m, r.element, r.right))
+	case pIsBlack(l) && pIsBlack(r):
+		m := pAppend(l.right, r.left)
+		if pIsRed(m) {
+			return newPNode(red,
+				newPNode(black, l.left, l.element, m.left),
+				m.element,
+				newPNode(black, m.right, r.element, r.right),
+			)
+		}
+		return pBalanceLeft(l.left, l.element, newPNode(black, m, r.element, r.right))
+	case pIsRed(r):
+		return newPNode(red, pAppend(l, r.left), r.element, r.right)
+	}
+
+	return newPNode(red, l.left, l.element, pAppend(l.right, r))
+}
+
+// pAscend calls fn for every element of persistent tree in key order while fn returns true
+func pAscend[V constraints.Ordered](n *pnode[V], fn func(key V, value any) bool) {
+	var stack []*pnode[V]
+	for n != nil || len(stack) > 0 {
+		for n != nil {
+			stack = append(stack, n)
+			n = n.left
+		}
+		n = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !fn(n.element.key, n.element.value) {
+			return
+		}
+		n = n.right
+	}
+}
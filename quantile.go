@@ -0,0 +1,114 @@
+package rbtree
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Interpolation is the method of computing quantile of numeric keys which is between two keys
+type Interpolation int
+
+const (
+	InterpolateLower    Interpolation = iota // the lower key
+	InterpolateHigher                        // the higher key
+	InterpolateNearest                       // the nearest key, the higher key if both are at the same distance
+	InterpolateMidpoint                      // mean of both keys
+	InterpolateLinear                        // linear interpolation between both keys
+)
+
+// Quantile is a function for getting q-quantile of keys in O(log n):
+// key at position floor(q * (Len() - 1)) in key order, e.g. q = 0.99 for p99.
+// Error is returned if tree is empty or q isn't in range [0, 1].
+// - param q should be in range [0, 1]
+func (t *OrderTree[V]) Quantile(q float64) (V, error) {
+	t.guard.startRead()
+	defer t.guard.endRead()
+
+	return t.quantile(0, t.size, q)
+}
+
+// Median is a function for getting median of keys in O(log n),
+// the lower one of two middle keys for even count of elements.
+// Error is returned if tree is empty.
+func (t *OrderTree[V]) Median() (V, error) {
+	return t.Quantile(0.5)
+}
+
+// QuantileRange is a function for getting q-quantile of keys in range [lo, hi) in O(log n) (see Quantile).
+// Error is returned if there are no keys in range or q isn't in range [0, 1].
+// - params lo and hi should be `ordered type` (`int`, `string`, `float` etc)
+// - param q should be in range [0, 1]
+func (t *OrderTree[V]) QuantileRange(lo, hi V, q float64) (V, error) {
+	t.guard.startRead()
+	defer t.guard.endRead()
+
+	return t.quantile(t.rank(lo), t.rank(hi), q)
+}
+
+// NumericQuantile is a function for getting q-quantile of numeric keys in O(log n).
+// Quantile is at position q * (Len() - 1) in key order,
+// interpolation sets result if position is between two keys.
+// Error is returned if tree is empty or q isn't in range [0, 1].
+// - param t is tree with numeric keys (`int`, `uint`, `float` etc)
+// - param q should be in range [0, 1]
+// - param interpolation is one of InterpolateLower, InterpolateHigher, InterpolateNearest,
+// InterpolateMidpoint and InterpolateLinear
+func NumericQuantile[V Number](t *OrderTree[V], q float64, interpolation Interpolation) (float64, error) {
+	t.guard.startRead()
+	defer t.guard.endRead()
+
+	if interpolation < InterpolateLower || interpolation > InterpolateLinear {
+		return 0, errors.New(fmt.Sprintf("unknown interpolation %v", interpolation))
+	}
+	position, err := quantilePosition(t.size, q)
+	if err != nil {
+		return 0, err
+	}
+
+	i := math.Floor(position)
+	lower := float64(t.selectNode(int(i)).element.key)
+	if position == i {
+		return lower, nil
+	}
+	higher := float64(t.selectNode(int(i) + 1).element.key)
+
+	switch interpolation {
+	case InterpolateLower:
+		return lower, nil
+	case InterpolateHigher:
+		return higher, nil
+	case InterpolateNearest:
+		if position-i < 0.5 {
+			return lower, nil
+		}
+		return higher, nil
+	case InterpolateMidpoint:
+		return lower + (higher-lower)/2, nil
+	default:
+		return lower + (higher-lower)*(position-i), nil
+	}
+}
+
+// quantile - internal function for getting q-quantile of keys at positions [from, to)
+func (t *OrderTree[V]) quantile(from, to int, q float64) (V, error) {
+	var key V
+	position, err := quantilePosition(to-from, q)
+	if err != nil {
+		return key, err
+	}
+
+	return t.selectNode(from + int(position)).element.key, nil
+}
+
+// quantilePosition - internal function for getting position of q-quantile of count keys
+func quantilePosition(count int, q float64) (float64, error) {
+	if !(q >= 0 && q <= 1) {
+		return 0, errors.New(fmt.Sprintf("quantile %v out of range [0, 1]", q))
+	}
+	if count <= 0 {
+		return 0, errors.New("quantile of empty set of keys")
+	}
+
+	return q * float64(count-1), nil
+}
@@ -0,0 +1,83 @@
+package rbtree
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOrderTree_Quantile(t1 *testing.T) {
+	t := NewOrderTree[int](nil)
+	for key := 1; key <= 100; key++ {
+		t.Insert(key*10, nil)
+	}
+
+	tests := []struct {
+		name    string
+		q       float64
+		lo, hi  int
+		want    int
+		wantErr bool
+	}{
+		{name: "min", q: 0, lo: 0, hi: 2000, want: 10},
+		{name: "max", q: 1, lo: 0, hi: 2000, want: 1000},
+		{name: "p50", q: 0.5, lo: 0, hi: 2000, want: 500},
+		{name: "p99", q: 0.99, lo: 0, hi: 2000, want: 990},
+		{name: "range", q: 0.5, lo: 100, hi: 200, want: 140},
+		{name: "range max", q: 1, lo: 100, hi: 200, want: 190},
+		{name: "empty range", q: 0.5, lo: 101, hi: 109, wantErr: true},
+		{name: "q out of range", q: 1.5, lo: 0, hi: 2000, wantErr: true},
+		{name: "NaN", q: math.NaN(), lo: 0, hi: 2000, wantErr: true},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			got, err := t.QuantileRange(tt.lo, tt.hi, tt.q)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t1.Errorf("QuantileRange(%v, %v, %v) = %v, %v, want %v", tt.lo, tt.hi, tt.q, got, err, tt.want)
+			}
+		})
+	}
+
+	if got, err := t.Quantile(0.99); err != nil || got != 990 {
+		t1.Errorf("Quantile(0.99) = %v, %v, want 990", got, err)
+	}
+	t.Delete(1000)
+	if got, err := t.Median(); err != nil || got != 500 {
+		t1.Errorf("Median() = %v, %v, want 500", got, err)
+	}
+	if _, err := NewOrderTree[int](nil).Median(); err == nil {
+		t1.Errorf("Median() of empty tree error = nil")
+	}
+}
+
+func TestNumericQuantile(t1 *testing.T) {
+	t := NewOrderTree[int](nil)
+	for _, key := range []int{10, 20, 30, 40} {
+		t.Insert(key, nil)
+	}
+
+	tests := []struct {
+		name          string
+		q             float64
+		interpolation Interpolation
+		want          float64
+		wantErr       bool
+	}{
+		{name: "lower", q: 0.5, interpolation: InterpolateLower, want: 20},
+		{name: "higher", q: 0.5, interpolation: InterpolateHigher, want: 30},
+		{name: "nearest", q: 0.4, interpolation: InterpolateNearest, want: 20},
+		{name: "nearest at the same distance", q: 0.5, interpolation: InterpolateNearest, want: 30},
+		{name: "midpoint", q: 0.4, interpolation: InterpolateMidpoint, want: 25},
+		{name: "linear", q: 0.4, interpolation: InterpolateLinear, want: 22},
+		{name: "exact key", q: 1, interpolation: InterpolateLinear, want: 40},
+		{name: "unknown interpolation", q: 0.5, interpolation: Interpolation(10), wantErr: true},
+		{name: "q out of range", q: -0.1, interpolation: InterpolateLinear, wantErr: true},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			got, err := NumericQuantile(t, tt.q, tt.interpolation)
+			if (err != nil) != tt.wantErr || math.Abs(got-tt.want) > 1e-9 {
+				t1.Errorf("NumericQuantile(%v, %v) = %v, %v, want %v", tt.q, tt.interpolation, got, err, tt.want)
+			}
+		})
+	}
+}
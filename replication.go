@@ -0,0 +1,378 @@
+package rbtree
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sync"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Kinds of replication stream's frames
+const (
+	frameSnapshot byte = iota + 1
+	frameOp
+)
+
+// frame is the structure of one record of replication stream:
+// kind, uvarint seq, uvarint length of payload, payload and CRC32 of all previous bytes.
+// Payload of snapshot frame is tree in binary format, seq is the seq of last operation in snapshot.
+// Payload of operation frame is encoded like WAL record's payload
+type frame struct {
+	kind    byte
+	seq     uint64
+	payload []byte
+}
+
+// Leader is a goroutine-safe tree which records every change to in-memory operation log
+// and streams this log to followers by Serve.
+// Every change gets sequence number (seq), the first change has seq 1.
+// Only last retain operations are kept, follower which is behind them gets snapshot of tree first
+type Leader[V constraints.Ordered] struct {
+	mu     sync.Mutex
+	cond   *sync.Cond // signaled on every change and on Close
+	tree   *Tree[V]
+	codecs *codecs[V]
+	log    [][]byte // payloads of operations with seq from first to seq
+	first  uint64
+	seq    uint64
+	retain int
+	closed bool
+}
+
+// Follower is a goroutine-safe tree which applies replication stream of Leader.
+// Follower can be read during applying, it's always equal to some previous state of leader's tree
+type Follower[V constraints.Ordered] struct {
+	mu     sync.RWMutex
+	tree   *Tree[V]
+	codecs *codecs[V]
+	seq    uint64
+}
+
+// NewLeader is a function for creation empty leader tree
+// - param retain is max count of operations kept for followers, all operations are kept if it's <= 0
+// - param values is codec of values, GobValueCodec is used if it's nil
+func NewLeader[V constraints.Ordered](retain int, values ValueCodec) *Leader[V] {
+	t := &Leader[V]{
+		tree:   New[V](),
+		first:  1,
+		retain: retain,
+	}
+	t.tree.SetCodecs(nil, values)
+	t.codecs = t.tree.getCodecs()
+	t.cond = sync.NewCond(&t.mu)
+
+	return t
+}
+
+// Insert is a function for inserting element into tree and recording it to operation log.
+// If element with the same key exists, its value is replaced.
+// Error is returned if element can't be encoded, tree isn't changed in this case.
+// - param key should be `ordered type` (`int`, `string`, `float` etc.)
+// - param value can be any type supported by codec of values
+func (t *Leader[V]) Insert(key V, value any) error {
+	payload, err := appendOp(nil, t.codecs, walInsert, key, value)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.tree.Insert(key, value)
+	t.record(payload)
+
+	return nil
+}
+
+// Delete is a function for deleting element from tree and recording it to operation log.
+// Nothing is recorded if element doesn't exist.
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *Leader[V]) Delete(key V) error {
+	payload, err := appendOp(nil, t.codecs, walDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, removed := t.tree.remove(key); removed {
+		t.record(payload)
+	}
+
+	return nil
+}
+
+// Exists is a function for searching element in tree. If element exists in tree - return true, else - false
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *Leader[V]) Exists(key V) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.tree.Exists(key)
+}
+
+// GetValue is a function for searching element in tree and returning value of this element
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *Leader[V]) GetValue(key V) (any, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.tree.GetValue(key)
+}
+
+// Len is a function for getting count of elements in tree.
+func (t *Leader[V]) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.tree.Len()
+}
+
+// Seq is a function for getting seq of the last change, 0 if tree was never changed.
+func (t *Leader[V]) Seq() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.seq
+}
+
+// Serve is a function for streaming changes with seq greater than after to w.
+// New follower passes 0, reconnected follower passes its Seq to resume.
+// If some of needed operations are not kept anymore, snapshot of tree is written first.
+// Serve waits for new changes and returns when ctx is done (ctx.Err()), Leader is closed (nil)
+// or writing fails (error of w). Serve doesn't interrupt blocked write, close connection for it.
+func (t *Leader[V]) Serve(ctx context.Context, w io.Writer, after uint64) error {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			t.mu.Lock()
+			t.cond.Broadcast()
+			t.mu.Unlock()
+		case <-done:
+		}
+	}()
+
+	bw := bufio.NewWriter(w)
+	var b []byte
+	for {
+		t.mu.Lock()
+		if after > t.seq {
+			t.mu.Unlock()
+			return errors.New(fmt.Sprintf("follower's seq %v is ahead of leader's seq %v", after, t.seq))
+		}
+		for after == t.seq && !t.closed && ctx.Err() == nil {
+			t.cond.Wait()
+		}
+		if ctx.Err() != nil || t.closed && after == t.seq {
+			t.mu.Unlock()
+			return ctx.Err()
+		}
+
+		var frames []frame
+		if after+1 < t.first {
+			snapshot, err := t.tree.MarshalBinary()
+			if err != nil {
+				t.mu.Unlock()
+				return err
+			}
+			frames = append(frames, frame{kind: frameSnapshot, seq: t.seq, payload: snapshot})
+		} else {
+			for seq := after + 1; seq <= t.seq; seq++ {
+				frames = append(frames, frame{kind: frameOp, seq: seq, payload: t.log[seq-t.first]})
+			}
+		}
+		t.mu.Unlock()
+
+		for _, f := range frames {
+			b = appendFrame(b[:0], f)
+			if _, err := bw.Write(b); err != nil {
+				return err
+			}
+			after = f.seq
+		}
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+// Close is a function for stopping all Serve calls after they write all changes.
+// Leader can still be changed after Close, but changes are not streamed anymore.
+func (t *Leader[V]) Close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.closed = true
+	t.cond.Broadcast()
+}
+
+// record - internal function for appending operation to log and dropping operations which are not retained.
+// Lock should be held.
+func (t *Leader[V]) record(payload []byte) {
+	t.seq++
+	t.log = append(t.log, payload)
+	if t.retain > 0 && len(t.log) > t.retain {
+		t.log[0] = nil
+		t.log = t.log[1:]
+		t.first++
+	}
+	t.cond.Broadcast()
+}
+
+// NewFollower is a function for creation empty follower tree
+// - param values is codec of values, it should be the same as leader's codec
+func NewFollower[V constraints.Ordered](values ValueCodec) *Follower[V] {
+	t := &Follower[V]{tree: New[V]()}
+	t.tree.SetCodecs(nil, values)
+	t.codecs = t.tree.getCodecs()
+
+	return t
+}
+
+// Apply is a function for reading replication stream from r and applying it to tree.
+// It returns nil when r ends between frames, else - error of reading or decoding.
+// Operations which are already applied are skipped, so stream can be restarted from any seq <= Seq.
+func (t *Follower[V]) Apply(r io.Reader) error {
+	br := bufio.NewReader(r)
+	for {
+		f, err := readFrame(br)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := t.apply(f); err != nil {
+			return err
+		}
+	}
+}
+
+// Seq is a function for getting seq of the last applied change.
+// It should be passed to Leader.Serve to resume replication.
+func (t *Follower[V]) Seq() uint64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.seq
+}
+
+// Exists is a function for searching element in tree. If element exists in tree - return true, else - false
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *Follower[V]) Exists(key V) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.tree.Exists(key)
+}
+
+// GetValue is a function for searching element in tree and returning value of this element
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *Follower[V]) GetValue(key V) (any, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.tree.GetValue(key)
+}
+
+// Len is a function for getting count of elements in tree.
+func (t *Follower[V]) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.tree.Len()
+}
+
+// Ascend is a function for iterating over tree's elements in key order under read lock.
+// Iteration stops when fn returns false.
+// fn must not call any method of Follower: read lock isn't recursive, so even reads
+// (Exists, GetValue etc.) deadlock when Apply is waiting to change tree.
+// Collect elements in fn and use them after Ascend returns instead.
+func (t *Follower[V]) Ascend(fn func(key V, value any) bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	t.tree.Ascend(fn)
+}
+
+// apply - internal function for applying one frame of replication stream
+func (t *Follower[V]) apply(f frame) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch f.kind {
+	case frameSnapshot:
+		tree := New[V]()
+		tree.SetCodecs(t.codecs.keys, t.codecs.values)
+		if err := tree.UnmarshalBinary(f.payload); err != nil {
+			return err
+		}
+		t.tree = tree
+	case frameOp:
+		if f.seq <= t.seq {
+			return nil
+		}
+		if f.seq != t.seq+1 {
+			return errors.New(fmt.Sprintf("operation %v is received after %v", f.seq, t.seq))
+		}
+		if err := applyOp(t.tree, t.codecs, f.payload); err != nil {
+			return err
+		}
+	default:
+		return errors.New(fmt.Sprintf("unknown kind %v of replication frame", f.kind))
+	}
+	t.seq = f.seq
+
+	return nil
+}
+
+// appendFrame - internal function for encoding frame of replication stream
+func appendFrame(b []byte, f frame) []byte {
+	start := len(b)
+	b = append(b, f.kind)
+	b = binary.AppendUvarint(b, f.seq)
+	b = binary.AppendUvarint(b, uint64(len(f.payload)))
+	b = append(b, f.payload...)
+
+	return binary.BigEndian.AppendUint32(b, crc32.ChecksumIEEE(b[start:]))
+}
+
+// readFrame - internal function for decoding frame encoded by appendFrame.
+// It returns io.EOF only if r ends before frame.
+func readFrame(r *bufio.Reader) (frame, error) {
+	var f frame
+	kind, err := r.ReadByte()
+	if err != nil {
+		return f, err
+	}
+
+	cr := &checksumReader{r: r, crc: crc32.NewIEEE()}
+	cr.crc.Write([]byte{kind})
+	f.kind = kind
+	if f.seq, err = binary.ReadUvarint(cr); err != nil {
+		return f, err
+	}
+	if f.payload, err = cr.readBytes(); err != nil {
+		return f, err
+	}
+
+	sum := cr.crc.Sum32()
+	b := make([]byte, 4)
+	if err := cr.readFull(b); err != nil {
+		return f, err
+	}
+	if binary.BigEndian.Uint32(b) != sum {
+		return f, errors.New("checksum mismatch of replication frame")
+	}
+
+	return f, nil
+}
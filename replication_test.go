@@ -0,0 +1,179 @@
+package rbtree
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLeader_Serve(t1 *testing.T) {
+	tests := []struct {
+		name     string
+		retain   int
+		inserted int
+		deleted  int
+		wantKind byte
+	}{
+		{name: "all operations are kept", retain: 0, inserted: 100, deleted: 30, wantKind: frameOp},
+		{name: "operations are kept", retain: 200, inserted: 100, deleted: 30, wantKind: frameOp},
+		{name: "snapshot bootstrap", retain: 10, inserted: 100, deleted: 30, wantKind: frameSnapshot},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			leader := NewLeader[int](tt.retain, nil)
+			for i := 0; i < tt.inserted; i++ {
+				mustNoErr(t1, leader.Insert(i, i*10))
+			}
+			for i := 0; i < tt.deleted; i++ {
+				mustNoErr(t1, leader.Delete(i*3))
+			}
+
+			conn, peer := net.Pipe()
+			defer conn.Close()
+			go leader.Serve(context.Background(), peer, 0)
+
+			f, err := readFrame(bufio.NewReader(conn))
+			if err != nil {
+				t1.Fatalf("readFrame() error = %v", err)
+			}
+			if f.kind != tt.wantKind {
+				t1.Errorf("first frame kind = %v, want %v", f.kind, tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestFollower_Apply(t1 *testing.T) {
+	leader := NewLeader[int](20, nil)
+	for i := 0; i < 50; i++ {
+		mustNoErr(t1, leader.Insert(i, i))
+	}
+
+	// new follower gets snapshot and live changes
+	follower := NewFollower[int](nil)
+	stop := replicate(t1, leader, follower)
+	for i := 0; i < 50; i += 2 {
+		mustNoErr(t1, leader.Delete(i))
+	}
+	mustNoErr(t1, leader.Insert(100, "value"))
+	waitReplicated(t1, leader, follower)
+	stop()
+
+	// reconnected follower gets only missed operations
+	for i := 0; i < 10; i++ {
+		mustNoErr(t1, leader.Insert(i, -i))
+	}
+	conn, peer := net.Pipe()
+	go leader.Serve(context.Background(), peer, follower.Seq())
+	f, err := readFrame(bufio.NewReader(conn))
+	if err != nil || f.kind != frameOp || f.seq != follower.Seq()+1 {
+		t1.Errorf("first frame after resume = %v, %v, %v, want operation %v", f.kind, f.seq, err, follower.Seq()+1)
+	}
+	conn.Close()
+
+	stop = replicate(t1, leader, follower)
+	waitReplicated(t1, leader, follower)
+	stop()
+
+	// the same operations are skipped
+	conn, peer = net.Pipe()
+	go func() {
+		leader.Serve(context.Background(), peer, follower.Seq()-5)
+		peer.Close()
+	}()
+	leader.Close()
+	mustNoErr(t1, follower.Apply(conn))
+	waitReplicated(t1, leader, follower)
+}
+
+func TestLeader_Serve_errors(t1 *testing.T) {
+	leader := NewLeader[int](0, nil)
+	mustNoErr(t1, leader.Insert(1, 1))
+
+	conn, peer := net.Pipe()
+	defer conn.Close()
+	if err := leader.Serve(context.Background(), peer, 2); err == nil {
+		t1.Errorf("Serve() of follower ahead of leader error = nil")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error)
+	go func() { result <- leader.Serve(ctx, peer, 1) }()
+	cancel()
+	if err := <-result; !errors.Is(err, context.Canceled) {
+		t1.Errorf("Serve() after cancel error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestFollower_Apply_gap(t1 *testing.T) {
+	var b []byte
+	b = appendFrame(b, frame{kind: frameOp, seq: 2, payload: []byte{walDelete, 1, 2}})
+
+	if err := NewFollower[int](nil).Apply(streamOf(b)); err == nil {
+		t1.Errorf("Apply() of operation after gap error = nil")
+	}
+
+	b[len(b)-1]++
+	if err := NewFollower[int](nil).Apply(streamOf(b)); err == nil {
+		t1.Errorf("Apply() of corrupted frame error = nil")
+	}
+}
+
+// streamOf returns connection which streams b and ends
+func streamOf(b []byte) net.Conn {
+	conn, peer := net.Pipe()
+	go func() {
+		peer.Write(b)
+		peer.Close()
+	}()
+
+	return conn
+}
+
+// replicate starts streaming from leader to follower over net.Pipe and returns function which stops it
+func replicate(t1 *testing.T, leader *Leader[int], follower *Follower[int]) func() {
+	conn, peer := net.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan struct{})
+	go func() {
+		defer close(served)
+		leader.Serve(ctx, peer, follower.Seq())
+		peer.Close()
+	}()
+
+	applied := make(chan error)
+	go func() { applied <- follower.Apply(conn) }()
+
+	return func() {
+		cancel()
+		<-served
+		if err := <-applied; err != nil {
+			t1.Errorf("Apply() error = %v", err)
+		}
+	}
+}
+
+// waitReplicated waits until follower applies all changes of leader and compares their elements
+func waitReplicated(t1 *testing.T, leader *Leader[int], follower *Follower[int]) {
+	deadline := time.Now().Add(5 * time.Second)
+	for follower.Seq() != leader.Seq() {
+		if time.Now().After(deadline) {
+			t1.Fatalf("follower's seq = %v, leader's seq = %v", follower.Seq(), leader.Seq())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	leader.mu.Lock()
+	want := leader.tree.elements()
+	leader.mu.Unlock()
+	follower.mu.RLock()
+	got := follower.tree.elements()
+	follower.mu.RUnlock()
+	if !reflect.DeepEqual(got, want) {
+		t1.Errorf("follower's elements = %v, want %v", got, want)
+	}
+}
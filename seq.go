@@ -0,0 +1,318 @@
+package rbtree
+
+import (
+	"errors"
+	"fmt"
+)
+
+// seqNilNode is the nilNode of all sequences. It's never changed, so nodes can be moved between sequences
+// by Concat and SplitAt without relinking of leaves
+var seqNilNode = &node[int]{color: black}
+
+// Seq is a sequence of values which are addressed by position instead of key (implicit keys, e.g. rope).
+// Every node keeps size of its subtree, so position of node is found in O(log n).
+// Insert, delete, access, Concat and SplitAt take O(log n).
+// Seq has no byte accounting: bytes of its tree are always 0
+type Seq[V any] struct {
+	tree *Tree[int] // keys aren't used, values are V
+}
+
+// seqSizer is the augmentation of Seq: size of subtree
+type seqSizer struct{}
+
+// NewSeq is a function for creation empty sequence
+// - param should be any type of values
+func NewSeq[V any]() *Seq[V] {
+	return &Seq[V]{tree: newSeqTree()}
+}
+
+// Len is a function for getting count of values in sequence.
+func (s *Seq[V]) Len() int {
+	return s.tree.Len()
+}
+
+// At is a function for getting value at position i in O(log n)
+// - param i should be in range [0, Len())
+func (s *Seq[V]) At(i int) (V, error) {
+	s.tree.guard.startRead()
+	defer s.tree.guard.endRead()
+
+	var result V
+	if i < 0 || i >= s.tree.size {
+		return result, errors.New(fmt.Sprintf("index %v out of range [0, %v)", i, s.tree.size))
+	}
+
+	return s.at(i).element.value.(V), nil
+}
+
+// InsertAt is a function for inserting value at position i in O(log n),
+// values at positions >= i are moved to the next positions
+// - param i should be in range [0, Len()]
+// - param value can be any value of type V
+func (s *Seq[V]) InsertAt(i int, value V) error {
+	s.tree.guard.startWrite()
+	defer s.tree.guard.endWrite()
+
+	t := s.tree
+	if i < 0 || i > t.size {
+		return errors.New(fmt.Sprintf("index %v out of range [0, %v]", i, t.size))
+	}
+
+	n := t.getNewNode(0, value)
+	t.size++
+	if t.root == t.nilNode {
+		t.root = n
+		t.insertFixup(n)
+		return nil
+	}
+
+	// new node is attached after the last node before position i
+	var parent *node[int]
+	if i == t.size-1 {
+		parent = t.max(t.root)
+		parent.right = n
+	} else if next := s.at(i); next.left == t.nilNode {
+		parent = next
+		parent.left = n
+	} else {
+		parent = t.max(next.left)
+		parent.right = n
+	}
+	n.parent = parent
+	t.pullPath(parent)
+	t.insertFixup(n)
+
+	return nil
+}
+
+// DeleteAt is a function for deleting value at position i in O(log n),
+// values at positions > i are moved to the previous positions
+// - param i should be in range [0, Len())
+func (s *Seq[V]) DeleteAt(i int) error {
+	s.tree.guard.startWrite()
+	defer s.tree.guard.endWrite()
+
+	if i < 0 || i >= s.tree.size {
+		return errors.New(fmt.Sprintf("index %v out of range [0, %v)", i, s.tree.size))
+	}
+	s.tree.unlinkNode(s.at(i))
+
+	return nil
+}
+
+// Slice is a function for getting values at positions [i, j) in O(log n + j - i)
+// - params i and j should be in range [0, Len()], i <= j
+func (s *Seq[V]) Slice(i, j int) ([]V, error) {
+	s.tree.guard.startRead()
+	defer s.tree.guard.endRead()
+
+	if i < 0 || j > s.tree.size || i > j {
+		return nil, errors.New(fmt.Sprintf("slice [%v, %v) out of range [0, %v]", i, j, s.tree.size))
+	}
+
+	result := make([]V, 0, j-i)
+	if i == j {
+		return result, nil
+	}
+	s.tree.ascendFrom(s.at(i), func(key int, value any) bool {
+		result = append(result, value.(V))
+		return len(result) < j-i
+	})
+
+	return result, nil
+}
+
+// Concat is a function for moving all values of other sequence to the end of sequence in O(log n).
+// Other sequence becomes empty. Concat of sequence with itself does nothing.
+func (s *Seq[V]) Concat(other *Seq[V]) {
+	if other == s {
+		return
+	}
+
+	s.tree.guard.startWrite()
+	defer s.tree.guard.endWrite()
+	other.tree.guard.startWrite()
+	defer other.tree.guard.endWrite()
+
+	if other.tree.size == 0 {
+		return
+	}
+
+	// the first node of other sequence joins both trees
+	pivot := other.at(0)
+	other.tree.unlinkNode(pivot)
+	s.tree.root, _ = joinSeq(s.tree, s.tree.root, blackHeight(s.tree.root), pivot, other.tree.root, blackHeight(other.tree.root))
+	s.tree.size = seqSize(s.tree.root)
+	other.tree.root = seqNilNode
+	other.tree.size = 0
+}
+
+// SplitAt is a function for moving values at positions >= i to new sequence in O(log n).
+// Sequence keeps values at positions < i.
+// - param i should be in range [0, Len()]
+func (s *Seq[V]) SplitAt(i int) (*Seq[V], error) {
+	s.tree.guard.startWrite()
+	defer s.tree.guard.endWrite()
+
+	if i < 0 || i > s.tree.size {
+		return nil, errors.New(fmt.Sprintf("index %v out of range [0, %v]", i, s.tree.size))
+	}
+
+	right := NewSeq[V]()
+	s.tree.root, _, right.tree.root, _ = splitSeq(s.tree, s.tree.root, i, blackHeight(s.tree.root))
+	s.tree.size, right.tree.size = seqSize(s.tree.root), seqSize(right.tree.root)
+
+	return right, nil
+}
+
+// at - internal function for searching node at position i, i should be in range [0, Len())
+func (s *Seq[V]) at(i int) *node[int] {
+	n := s.tree.root
+	for {
+		left := seqSize(n.left)
+		switch {
+		case i < left:
+			n = n.left
+		case i == left:
+			return n
+		default:
+			i -= left + 1
+			n = n.right
+		}
+	}
+}
+
+// newSeqTree - internal function for creation tree of sequence with shared nilNode
+func newSeqTree() *Tree[int] {
+	return &Tree[int]{
+		root:    seqNilNode,
+		nilNode: seqNilNode,
+		aug:     seqSizer{},
+	}
+}
+
+// splitSeq - internal function for splitting subtree n of black height h into trees of its first i nodes
+// and of the rest nodes. It returns roots and black heights of both trees.
+// Each level joins subtree of smaller height, so split takes O(log n).
+// t is tree of sequence which is used for rebalancing, its root is changed
+func splitSeq(t *Tree[int], n *node[int], i, h int) (*node[int], int, *node[int], int) {
+	if n == seqNilNode {
+		return seqNilNode, 0, seqNilNode, 0
+	}
+
+	// both children of node have the same black height
+	childHeight := h
+	if n.color == black {
+		childHeight--
+	}
+	left, right := n.left, n.right
+	if i <= seqSize(left) {
+		l, lHeight, r, rHeight := splitSeq(t, left, i, childHeight)
+		r, rHeight = joinSeq(t, r, rHeight, n, right, childHeight)
+		return l, lHeight, r, rHeight
+	}
+
+	l, lHeight, r, rHeight := splitSeq(t, right, i-seqSize(left)-1, childHeight)
+	l, lHeight = joinSeq(t, left, childHeight, n, l, lHeight)
+
+	return l, lHeight, r, rHeight
+}
+
+// joinSeq - internal function for joining trees l and r of black heights lHeight and rHeight
+// with node k between them in O(|lHeight - rHeight| + 1).
+// Roots of l and r become black, k is attached as red node in place of black node of the higher tree
+// with the same black height as the lower tree, then red parent of k is fixed like after inserting.
+// t is tree of sequence which is used for rebalancing, its root is changed.
+// It returns root and black height of joined tree.
+func joinSeq(t *Tree[int], l *node[int], lHeight int, k, r *node[int], rHeight int) (*node[int], int) {
+	if l != seqNilNode {
+		l.parent = seqNilNode
+		if l.color == red {
+			l.color = black
+			lHeight++
+		}
+	}
+	if r != seqNilNode {
+		r.parent = seqNilNode
+		if r.color == red {
+			r.color = black
+			rHeight++
+		}
+	}
+
+	k.color = red
+	parent, c, height := seqNilNode, l, lHeight
+	if lHeight >= rHeight {
+		t.root = l
+		for h := lHeight; c.color != black || h != rHeight; c = c.right {
+			if c.color == black {
+				h--
+			}
+			parent = c
+		}
+		k.left, k.right = c, r
+	} else {
+		t.root, c, height = r, r, rHeight
+		for h := rHeight; c.color != black || h != lHeight; c = c.left {
+			if c.color == black {
+				h--
+			}
+			parent = c
+		}
+		k.left, k.right = l, c
+	}
+
+	k.parent = parent
+	for _, child := range []*node[int]{k.left, k.right} {
+		if child != seqNilNode {
+			child.parent = k
+		}
+	}
+	switch {
+	case parent == seqNilNode:
+		t.root = k
+	case lHeight >= rHeight:
+		parent.right = k
+	default:
+		parent.left = k
+	}
+	t.pull(k)
+	t.pullPath(parent)
+	if t.insertFixup(k) {
+		height++
+	}
+
+	return t.root, height
+}
+
+// blackHeight - internal function for getting count of black nodes on path from n to leaf
+func blackHeight(n *node[int]) int {
+	h := 0
+	for ; n != seqNilNode; n = n.left {
+		if n.color == black {
+			h++
+		}
+	}
+
+	return h
+}
+
+func (seqSizer) pull(n *node[int]) {
+	p, ok := n.agg().(*int)
+	if !ok {
+		p = new(int)
+		n.setAgg(p)
+	}
+	*p = seqSize(n.left) + 1 + seqSize(n.right)
+}
+
+func (seqSizer) push(n *node[int]) {}
+
+// seqSize - internal function for getting size of subtree, size of nilNode is 0
+func seqSize(n *node[int]) int {
+	if p, ok := n.agg().(*int); ok {
+		return *p
+	}
+
+	return 0
+}
@@ -0,0 +1,163 @@
+package rbtree
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestSeq(t1 *testing.T) {
+	s := NewSeq[int]()
+	var want []int
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 3000; i++ {
+		switch op := rng.Intn(10); {
+		case op < 2 && len(want) > 0:
+			j := rng.Intn(len(want))
+			mustNoErr(t1, s.DeleteAt(j))
+			want = append(want[:j], want[j+1:]...)
+		case op < 3:
+			j := rng.Intn(len(want) + 1)
+			right, err := s.SplitAt(j)
+			mustNoErr(t1, err)
+			checkSeq(t1, s)
+			checkSeq(t1, right)
+			if got, _ := right.Slice(0, right.Len()); !reflect.DeepEqual(got, append([]int{}, want[j:]...)) {
+				t1.Fatalf("SplitAt(%v) = %v, want %v", j, got, want[j:])
+			}
+
+			// other tree is concatenated back after some new values
+			other := NewSeq[int]()
+			for k, n := 0, rng.Intn(20); k < n; k++ {
+				mustNoErr(t1, other.InsertAt(k, -k))
+				want = append(want[:j+k], append([]int{-k}, want[j+k:]...)...)
+			}
+			s.Concat(other)
+			s.Concat(right)
+			if other.Len() != 0 || right.Len() != 0 {
+				t1.Fatalf("Concat() left %v and %v values in other sequences", other.Len(), right.Len())
+			}
+		default:
+			j := rng.Intn(len(want) + 1)
+			mustNoErr(t1, s.InsertAt(j, i))
+			want = append(want[:j], append([]int{i}, want[j:]...)...)
+		}
+		checkSeq(t1, s)
+
+		if got, _ := s.Slice(0, s.Len()); !reflect.DeepEqual(got, append([]int{}, want...)) {
+			t1.Fatalf("Slice() = %v, want %v", got, want)
+		}
+	}
+
+	for i, value := range want {
+		if got, err := s.At(i); err != nil || got != value {
+			t1.Errorf("At(%v) = %v, %v, want %v", i, got, err, value)
+		}
+	}
+	if s.tree.Bytes() != 0 {
+		t1.Errorf("Bytes() = %v, sequence has no byte accounting", s.tree.Bytes())
+	}
+	if *seqNilNode != (node[int]{color: black}) {
+		t1.Errorf("nilNode of sequences is changed: %+v", *seqNilNode)
+	}
+}
+
+func TestSeq_errors(t1 *testing.T) {
+	s := NewSeq[string]()
+	for i, value := range []string{"a", "b", "c"} {
+		mustNoErr(t1, s.InsertAt(i, value))
+	}
+
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr bool
+	}{
+		{name: "At before start", call: func() error { _, err := s.At(-1); return err }, wantErr: true},
+		{name: "At end", call: func() error { _, err := s.At(3); return err }, wantErr: true},
+		{name: "At last", call: func() error { _, err := s.At(2); return err }},
+		{name: "InsertAt after end", call: func() error { return s.InsertAt(4, "x") }, wantErr: true},
+		{name: "DeleteAt end", call: func() error { return s.DeleteAt(3) }, wantErr: true},
+		{name: "Slice inverted", call: func() error { _, err := s.Slice(2, 1); return err }, wantErr: true},
+		{name: "Slice after end", call: func() error { _, err := s.Slice(1, 4); return err }, wantErr: true},
+		{name: "Slice empty", call: func() error { _, err := s.Slice(3, 3); return err }},
+		{name: "SplitAt after end", call: func() error { _, err := s.SplitAt(4); return err }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			if err := tt.call(); (err != nil) != tt.wantErr {
+				t1.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	s.Concat(s)
+	if got, _ := s.Slice(0, s.Len()); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t1.Errorf("sequence is changed by errors: %v", got)
+	}
+}
+
+// checkSeq checks red-black properties, parent links and sizes of sequence's tree
+func checkSeq[V any](t1 *testing.T, s *Seq[V]) {
+	t := s.tree
+	if t.root.color != black || t.root != t.nilNode && t.root.parent != t.nilNode {
+		t1.Fatalf("root is not black or has parent")
+	}
+
+	var check func(n *node[int]) (int, int)
+	check = func(n *node[int]) (int, int) {
+		if n == t.nilNode {
+			return 1, 0
+		}
+		if n.color == red && (n.left.color == red || n.right.color == red) {
+			t1.Fatalf("red node %v has red child", n.element.value)
+		}
+		if n.left != t.nilNode && n.left.parent != n || n.right != t.nilNode && n.right.parent != n {
+			t1.Fatalf("node %v has wrong parent links", n.element.value)
+		}
+
+		left, leftSize := check(n.left)
+		right, rightSize := check(n.right)
+		if left != right {
+			t1.Fatalf("node %v has different black heights %v and %v", n.element.value, left, right)
+		}
+		if size := leftSize + 1 + rightSize; seqSize(n) != size {
+			t1.Fatalf("node %v has size %v, want %v", n.element.value, seqSize(n), size)
+		}
+		if n.color == black {
+			left++
+		}
+
+		return left, seqSize(n)
+	}
+	if _, size := check(t.root); size != s.Len() {
+		t1.Fatalf("sequence has %v values, Len() = %v", size, s.Len())
+	}
+}
+
+func TestSplitSeq_heights(t1 *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for n := 0; n < 300; n += 1 + n/4 {
+		s := NewSeq[int]()
+		for i := 0; i < n; i++ {
+			mustNoErr(t1, s.InsertAt(rng.Intn(i+1), i))
+		}
+
+		// heights are passed down instead of being recomputed by every join
+		i := rng.Intn(n + 1)
+		l, lHeight, r, rHeight := splitSeq(s.tree, s.tree.root, i, blackHeight(s.tree.root))
+		if lHeight != blackHeight(l) || rHeight != blackHeight(r) {
+			t1.Fatalf("splitSeq() of %v values at %v = heights %v and %v, want %v and %v",
+				n, i, lHeight, rHeight, blackHeight(l), blackHeight(r))
+		}
+		if seqSize(l) != i || seqSize(r) != n-i {
+			t1.Fatalf("splitSeq() of %v values at %v = sizes %v and %v", n, i, seqSize(l), seqSize(r))
+		}
+
+		root, height := joinSeq(s.tree, l, lHeight, s.tree.getNewNode(0, -1), r, rHeight)
+		if height != blackHeight(root) || seqSize(root) != n+1 {
+			t1.Fatalf("joinSeq() = height %v and size %v, want %v and %v", height, seqSize(root), blackHeight(root), n+1)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package rbtree
+
+import (
+	"sort"
+	"sync"
+
+	"golang.org/x/exp/constraints"
+)
+
+// ShardedTree is a goroutine-safe tree which partitions key space into ranges.
+// Every range (shard) is a separate Tree with its own lock,
+// so writes to different shards don't block each other.
+// Shard which has more than maxShardLen elements is split in two,
+// empty shard or two neighbouring shards which have less than maxShardLen/4 elements together are joined
+type ShardedTree[V constraints.Ordered] struct {
+	mu          sync.RWMutex // guards list of shards, writers are rebalancing functions only
+	shards      []*shard[V]
+	maxShardLen int
+}
+
+// shard is the structure of one key range of ShardedTree.
+// shard contains keys >= lo (first shard contains all keys less than lo of second shard)
+type shard[V constraints.Ordered] struct {
+	mu   sync.RWMutex
+	lo   V
+	tree *Tree[V]
+}
+
+// NewShardedTree is a function for creation empty sharded tree
+// - param maxShardLen is max count of elements in shard, shards are never split if it's <= 0
+// - param bounds are keys which divide key space into shards, order of bounds doesn't matter and equal bounds are ignored
+func NewShardedTree[V constraints.Ordered](maxShardLen int, bounds ...V) *ShardedTree[V] {
+	t := &ShardedTree[V]{
+		shards:      []*shard[V]{{tree: New[V]()}},
+		maxShardLen: maxShardLen,
+	}
+	// bounds are copied: caller's slice isn't reordered
+	sorted := append([]V(nil), bounds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	for i, lo := range sorted {
+		if i > 0 && lo == sorted[i-1] {
+			continue // equal bounds would create empty shard which can't be reached
+		}
+		t.shards = append(t.shards, &shard[V]{lo: lo, tree: New[V]()})
+	}
+
+	return t
+}
+
+// Insert is a function for inserting element into tree.
+// If element with the same key exists, its value is replaced.
+// - param key should be `ordered type` (`int`, `string`, `float` etc.)
+// - param value can be any type
+func (t *ShardedTree[V]) Insert(key V, value any) {
+	t.mu.RLock()
+	s := t.shard(key)
+	s.mu.Lock()
+	s.tree.Insert(key, value)
+	hot := t.maxShardLen > 0 && s.tree.Len() > t.maxShardLen
+	s.mu.Unlock()
+	t.mu.RUnlock()
+
+	if hot {
+		t.rebalance()
+	}
+}
+
+// Delete is a function for deleting element from tree
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *ShardedTree[V]) Delete(key V) {
+	t.mu.RLock()
+	s := t.shard(key)
+	s.mu.Lock()
+	_, removed := s.tree.remove(key)
+	cold := removed && t.maxShardLen > 0 && len(t.shards) > 1 && s.tree.Len() == 0
+	s.mu.Unlock()
+	t.mu.RUnlock()
+
+	if cold {
+		t.rebalance()
+	}
+}
+
+// Exists is a function for searching element in tree. If element exists in tree - return true, else - false
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *ShardedTree[V]) Exists(key V) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	s := t.shard(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.tree.Exists(key)
+}
+
+// GetValue is a function for searching element in tree and returning value of this element
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *ShardedTree[V]) GetValue(key V) (any, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	s := t.shard(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.tree.GetValue(key)
+}
+
+// Len is a function for getting count of elements in tree.
+func (t *ShardedTree[V]) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	result := 0
+	for _, s := range t.shards {
+		s.mu.RLock()
+		result += s.tree.Len()
+		s.mu.RUnlock()
+	}
+
+	return result
+}
+
+// Min is a function for searching min element in tree (by key).
+func (t *ShardedTree[V]) Min() V {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, s := range t.shards {
+		s.mu.RLock()
+		result, ok := s.tree.Min(), s.tree.Len() > 0
+		s.mu.RUnlock()
+		if ok {
+			return result
+		}
+	}
+
+	var result V
+	return result
+}
+
+// Max is a function for searching max element in tree (by key).
+func (t *ShardedTree[V]) Max() V {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for i := len(t.shards) - 1; i >= 0; i-- {
+		s := t.shards[i]
+		s.mu.RLock()
+		result, ok := s.tree.Max(), s.tree.Len() > 0
+		s.mu.RUnlock()
+		if ok {
+			return result
+		}
+	}
+
+	var result V
+	return result
+}
+
+// Ascend is a function for iterating over tree's elements in key order.
+// Shards are iterated one by one under read lock of current shard,
+// so every shard is seen in consistent state, but the whole tree is not.
+// fn must not call any method of ShardedTree: read locks aren't recursive, so even reads
+// (Exists, GetValue etc.) deadlock when a writer is waiting.
+// Iteration stops when fn returns false.
+func (t *ShardedTree[V]) Ascend(fn func(key V, value any) bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, s := range t.shards {
+		stopped := false
+		s.mu.RLock()
+		s.tree.Ascend(func(key V, value any) bool {
+			stopped = !fn(key, value)
+			return !stopped
+		})
+		s.mu.RUnlock()
+		if stopped {
+			return
+		}
+	}
+}
+
+// Shards is a function for getting count of shards.
+func (t *ShardedTree[V]) Shards() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return len(t.shards)
+}
+
+// shard - internal function for searching shard of key. Read lock of tree should be held.
+func (t *ShardedTree[V]) shard(key V) *shard[V] {
+	i := sort.Search(len(t.shards)-1, func(i int) bool {
+		return key < t.shards[i+1].lo
+	})
+
+	return t.shards[i]
+}
+
+// rebalance - internal function for splitting hot shards and joining cold neighbours.
+// It holds write lock of tree, so all shards are free.
+func (t *ShardedTree[V]) rebalance() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	shards := make([]*shard[V], 0, len(t.shards)+1)
+	for _, s := range t.shards {
+		for s.tree.Len() > t.maxShardLen {
+			lo := middleKey(s.tree)
+			shards = append(shards, s)
+			s = &shard[V]{lo: lo, tree: s.tree.Split(lo)}
+		}
+
+		if len(shards) > 0 {
+			prev := shards[len(shards)-1]
+			if prev.tree.Len() == 0 || s.tree.Len() == 0 || prev.tree.Len()+s.tree.Len() < t.maxShardLen/4 {
+				// keys of s are greater than keys of prev, so Join can't fail
+				_ = prev.tree.Join(s.tree)
+				continue
+			}
+		}
+		shards = append(shards, s)
+	}
+	t.shards = shards
+}
+
+// middleKey - internal function for getting key at position Len()/2 of non-empty tree without copying of elements
+func middleKey[V constraints.Ordered](t *Tree[V]) V {
+	n := t.min(t.root)
+	for i := 0; i < t.Len()/2; i++ {
+		n = t.successor(n)
+	}
+
+	return n.element.key
+}
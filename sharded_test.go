@@ -0,0 +1,128 @@
+package rbtree
+
+import (
+	"math/rand"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestShardedTree_Routing(t1 *testing.T) {
+	t := NewShardedTree[int](0, 100, 10, 50)
+	if got := t.Shards(); got != 4 {
+		t1.Fatalf("Shards() = %v, want 4", got)
+	}
+
+	for _, key := range []int{5, 10, 49, 50, 99, 100, 1000, -1} {
+		t.Insert(key, key)
+	}
+	for i, want := range []int{2, 2, 2, 2} {
+		if got := t.shards[i].tree.Len(); got != want {
+			t1.Errorf("shard %v has %v elements, want %v", i, got, want)
+		}
+	}
+
+	var got []int
+	t.Ascend(func(key int, value any) bool {
+		got = append(got, key)
+		return key < 99
+	})
+	if want := []int{-1, 5, 10, 49, 50, 99}; !reflect.DeepEqual(got, want) {
+		t1.Errorf("Ascend() = %v, want %v", got, want)
+	}
+	if t.Min() != -1 || t.Max() != 1000 || t.Len() != 8 {
+		t1.Errorf("Min() = %v, Max() = %v, Len() = %v", t.Min(), t.Max(), t.Len())
+	}
+
+	t.Delete(10)
+	if t.Exists(10) || !t.Exists(49) {
+		t1.Errorf("Delete() removed wrong element")
+	}
+	if value, err := t.GetValue(50); err != nil || value != 50 {
+		t1.Errorf("GetValue() = %v, %v, want 50", value, err)
+	}
+}
+
+func TestNewShardedTree_bounds(t1 *testing.T) {
+	bounds := []int{100, 10, 50, 10}
+	t := NewShardedTree[int](0, bounds...)
+
+	if !reflect.DeepEqual(bounds, []int{100, 10, 50, 10}) {
+		t1.Errorf("NewShardedTree() reordered bounds: %v", bounds)
+	}
+	if got := t.Shards(); got != 4 {
+		t1.Errorf("Shards() with equal bounds = %v, want 4", got)
+	}
+	for i, want := range []int{0, 10, 50, 100} {
+		if i > 0 && t.shards[i].lo != want {
+			t1.Errorf("shard %v starts at %v, want %v", i, t.shards[i].lo, want)
+		}
+	}
+}
+
+func TestShardedTree_Rebalance(t1 *testing.T) {
+	t := NewShardedTree[int](16)
+	for i := 0; i < 200; i++ {
+		t.Insert(i, i)
+	}
+	if t.Shards() < 200/16 {
+		t1.Errorf("hot shards weren't split, Shards() = %v", t.Shards())
+	}
+	for _, s := range t.shards {
+		if s.tree.Len() > 16 {
+			t1.Errorf("shard has %v elements", s.tree.Len())
+		}
+		checkTreeProperties(t1, s.tree)
+	}
+
+	for i := 0; i < 190; i++ {
+		t.Delete(i)
+	}
+	if t.Len() != 10 || t.Min() != 190 {
+		t1.Errorf("Len() = %v, Min() = %v", t.Len(), t.Min())
+	}
+	if t.Shards() > 2 {
+		t1.Errorf("cold shards weren't joined, Shards() = %v", t.Shards())
+	}
+
+	prev := -1
+	t.Ascend(func(key int, value any) bool {
+		if key <= prev {
+			t1.Errorf("Ascend() key %v after %v", key, prev)
+		}
+		prev = key
+		return true
+	})
+}
+
+func TestShardedTree_Concurrent(t1 *testing.T) {
+	t := NewShardedTree[int](32, 250, 500, 750)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for i := 0; i < 500; i++ {
+				key := rng.Intn(1000)
+				if rng.Intn(4) == 0 {
+					t.Delete(key)
+					continue
+				}
+				t.Insert(key, key)
+				t.Exists(key)
+			}
+			t.Ascend(func(key int, value any) bool { return true })
+		}(int64(g))
+	}
+	wg.Wait()
+
+	count := 0
+	t.Ascend(func(key int, value any) bool {
+		count++
+		return true
+	})
+	if count != t.Len() {
+		t1.Errorf("Ascend() visited %v elements, Len() = %v", count, t.Len())
+	}
+}
@@ -0,0 +1,213 @@
+package rbtree
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/exp/constraints"
+)
+
+// ShiftTree is a tree with integer keys which supports shifting all keys >= from by delta in O(log n),
+// e.g. line numbers of text, where inserting a line shifts all later lines.
+// Shift of whole subtree is kept in subtree's root as offset of children's keys (lazy tag),
+// so node's key is relative to offsets of its ancestors. Offsets are moved to children
+// when tree is changed under the node, all methods present absolute keys
+type ShiftTree[V constraints.Signed] struct {
+	tree *Tree[V]
+}
+
+// shiftNode is the structure of ShiftTree's node augmentation: offset of keys of node's subtree except node itself
+type shiftNode[V constraints.Signed] struct {
+	tag V
+}
+
+// shifter is the augmentation of ShiftTree
+type shifter[V constraints.Signed] struct{}
+
+// NewShiftTree is a function for creation empty tree with shifting keys
+// - param should be signed integer type (`int`, `int64` etc)
+func NewShiftTree[V constraints.Signed]() *ShiftTree[V] {
+	t := New[V]()
+	t.aug = shifter[V]{}
+
+	return &ShiftTree[V]{tree: t}
+}
+
+// Insert is a function for inserting element into tree.
+// If element with the same key exists, its value is replaced.
+// - param key should be signed integer type (`int`, `int64` etc)
+// - param value can be any type
+func (t *ShiftTree[V]) Insert(key V, value any) {
+	t.tree.Insert(key, value)
+}
+
+// Delete is a function for deleting element from tree
+// - param key should be signed integer type (`int`, `int64` etc)
+func (t *ShiftTree[V]) Delete(key V) {
+	t.tree.guard.startWrite()
+	defer t.tree.guard.endWrite()
+
+	if n := t.search(key); n != nil {
+		t.tree.removeNode(n)
+	}
+}
+
+// Exists is a function for searching element in tree. If element exists in tree - return true, else - false
+// - param key should be signed integer type (`int`, `int64` etc)
+func (t *ShiftTree[V]) Exists(key V) bool {
+	t.tree.guard.startRead()
+	defer t.tree.guard.endRead()
+
+	return t.search(key) != nil
+}
+
+// GetValue is a function for searching element in tree and returning value of this element
+// - param key should be signed integer type (`int`, `int64` etc)
+func (t *ShiftTree[V]) GetValue(key V) (any, error) {
+	t.tree.guard.startRead()
+	defer t.tree.guard.endRead()
+
+	var result any
+	searchNode := t.search(key)
+	if searchNode == nil {
+		return result, errors.New(fmt.Sprintf("element with key %v not found", key))
+	}
+
+	return searchNode.element.value, nil
+}
+
+// Len is a function for getting count of elements in tree.
+func (t *ShiftTree[V]) Len() int {
+	return t.tree.Len()
+}
+
+// Ascend is a function for iterating over tree's elements in key order.
+// Iteration stops when fn returns false.
+func (t *ShiftTree[V]) Ascend(fn func(key V, value any) bool) {
+	t.tree.guard.startRead()
+	defer t.tree.guard.endRead()
+
+	t.ascend(t.tree.root, 0, fn)
+}
+
+// ShiftKeys is a function for adding delta to all keys >= from in O(log n).
+// Order of keys can't be changed: error is returned if negative delta moves key >= from
+// to key or below key of the greatest element with key < from, tree isn't changed in this case.
+// - params from and delta should be signed integer type (`int`, `int64` etc)
+func (t *ShiftTree[V]) ShiftKeys(from, delta V) error {
+	t.tree.guard.startWrite()
+	defer t.tree.guard.endWrite()
+
+	if delta < 0 {
+		lower, upper, hasLower, hasUpper := t.around(from)
+		if hasLower && hasUpper && upper+delta <= lower {
+			return errors.New(fmt.Sprintf("key %v shifted by %v collides with key %v", upper, delta, lower))
+		}
+	}
+
+	var offset V
+	n := t.tree.root
+	for n != t.tree.nilNode {
+		tag := shiftOf(n).tag
+		if n.element.key+offset >= from {
+			n.element.key += delta
+			applyShift(n.right, delta)
+			n = n.left
+		} else {
+			n = n.right
+		}
+		offset += tag
+	}
+
+	return nil
+}
+
+// search - internal function for searching node by absolute key. It returns nil if node doesn't exist.
+func (t *ShiftTree[V]) search(key V) *node[V] {
+	var offset V
+	n := t.tree.root
+	for n != t.tree.nilNode && key != n.element.key+offset {
+		if key < n.element.key+offset {
+			offset += shiftOf(n).tag
+			n = n.left
+			continue
+		}
+		offset += shiftOf(n).tag
+		n = n.right
+	}
+
+	if n == t.tree.nilNode {
+		return nil
+	}
+
+	return n
+}
+
+// around - internal function for searching the greatest absolute key < from and the smallest absolute key >= from
+func (t *ShiftTree[V]) around(from V) (lower, upper V, hasLower, hasUpper bool) {
+	var offset V
+	n := t.tree.root
+	for n != t.tree.nilNode {
+		key := n.element.key + offset
+		offset += shiftOf(n).tag
+		if key >= from {
+			upper, hasUpper = key, true
+			n = n.left
+			continue
+		}
+		lower, hasLower = key, true
+		n = n.right
+	}
+
+	return lower, upper, hasLower, hasUpper
+}
+
+// ascend - internal function for iterating over subtree n, offset is sum of tags of n's ancestors.
+// It returns false if fn stopped iteration.
+func (t *ShiftTree[V]) ascend(n *node[V], offset V, fn func(key V, value any) bool) bool {
+	if n == t.tree.nilNode {
+		return true
+	}
+
+	childOffset := offset + shiftOf(n).tag
+
+	return t.ascend(n.left, childOffset, fn) &&
+		fn(n.element.key+offset, n.element.value) &&
+		t.ascend(n.right, childOffset, fn)
+}
+
+func (shifter[V]) pull(n *node[V]) {
+	if n.agg() == nil {
+		n.setAgg(&shiftNode[V]{})
+	}
+}
+
+func (shifter[V]) push(n *node[V]) {
+	a := shiftOf(n)
+	if a.tag == 0 {
+		return
+	}
+
+	applyShift(n.left, a.tag)
+	applyShift(n.right, a.tag)
+	a.tag = 0
+}
+
+// shiftOf - internal function for getting augmentation of node, augmentation of nilNode is empty
+func shiftOf[V constraints.Signed](n *node[V]) *shiftNode[V] {
+	if a, ok := n.agg().(*shiftNode[V]); ok {
+		return a
+	}
+
+	return &shiftNode[V]{}
+}
+
+// applyShift - internal function for adding delta to all keys of subtree n
+func applyShift[V constraints.Signed](n *node[V], delta V) {
+	if n.agg() == nil {
+		return
+	}
+
+	n.element.key += delta
+	shiftOf(n).tag += delta
+}
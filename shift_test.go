@@ -0,0 +1,128 @@
+package rbtree
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestShiftTree_ShiftKeys(t1 *testing.T) {
+	t := NewShiftTree[int]()
+	want := map[int]int{}
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 3000; i++ {
+		key := rng.Intn(400) - 100
+		switch rng.Intn(5) {
+		case 0:
+			t.Delete(key)
+			delete(want, key)
+		case 1:
+			delta := rng.Intn(21) - 10
+			shifted := map[int]int{}
+			lower, upper, hasLower, hasUpper := 0, 0, false, false
+			for k, v := range want {
+				if k >= key {
+					shifted[k+delta] = v
+					if !hasUpper || k < upper {
+						upper, hasUpper = k, true
+					}
+					continue
+				}
+				shifted[k] = v
+				if !hasLower || k > lower {
+					lower, hasLower = k, true
+				}
+			}
+
+			err := t.ShiftKeys(key, delta)
+			if collides := hasLower && hasUpper && upper+delta <= lower; collides != (err != nil) {
+				t1.Fatalf("ShiftKeys(%v, %v) error = %v, want collision %v", key, delta, err, collides)
+			}
+			if err == nil {
+				want = shifted
+			}
+		default:
+			t.Insert(key, i)
+			want[key] = i
+		}
+
+		if got := shiftTreeKeys(t); !reflect.DeepEqual(got, sortedKeys(want)) {
+			t1.Fatalf("keys = %v, want %v", got, sortedKeys(want))
+		}
+	}
+
+	for key, value := range want {
+		if got, err := t.GetValue(key); err != nil || got != value {
+			t1.Errorf("GetValue(%v) = %v, %v, want %v", key, got, err, value)
+		}
+	}
+	if t.Len() != len(want) {
+		t1.Errorf("Len() = %v, want %v", t.Len(), len(want))
+	}
+}
+
+func TestShiftTree_lines(t1 *testing.T) {
+	t := NewShiftTree[int]()
+	for line := 1; line <= 5; line++ {
+		t.Insert(line, line*10)
+	}
+
+	// insert line before line 3
+	mustNoErr(t1, t.ShiftKeys(3, 1))
+	t.Insert(3, 100)
+	// delete line 2
+	t.Delete(2)
+	mustNoErr(t1, t.ShiftKeys(3, -1))
+
+	tests := []struct {
+		name  string
+		key   int
+		want  any
+		found bool
+	}{
+		{name: "line before changes", key: 1, want: 10, found: true},
+		{name: "inserted line", key: 2, want: 100, found: true},
+		{name: "shifted line", key: 3, want: 30, found: true},
+		{name: "last line", key: 5, want: 50, found: true},
+		{name: "after last line", key: 6, found: false},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			got, err := t.GetValue(tt.key)
+			if (err == nil) != tt.found || got != tt.want {
+				t1.Errorf("GetValue(%v) = %v, %v, want %v", tt.key, got, err, tt.want)
+			}
+			if t.Exists(tt.key) != tt.found {
+				t1.Errorf("Exists(%v) = %v, want %v", tt.key, !tt.found, tt.found)
+			}
+		})
+	}
+
+	if err := t.ShiftKeys(3, -2); err == nil {
+		t1.Errorf("ShiftKeys() with collision error = nil")
+	}
+}
+
+// shiftTreeKeys returns keys of tree in order of Ascend
+func shiftTreeKeys(t *ShiftTree[int]) []int {
+	keys := []int{}
+	t.Ascend(func(key int, value any) bool {
+		keys = append(keys, key)
+		return true
+	})
+
+	return keys
+}
+
+// sortedKeys returns sorted keys of map
+func sortedKeys(m map[int]int) []int {
+	keys := []int{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+
+	return keys
+}
@@ -0,0 +1,54 @@
+package rbtree
+
+import (
+	"reflect"
+	"unsafe"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Sizer is an interface of keys and values which know how much memory they hold in bytes.
+// Tree uses it for estimation of its size
+type Sizer interface {
+	Size() int
+}
+
+// Bytes is a function for getting estimated size of tree's memory in bytes.
+// Estimation includes nodes, contents of string keys and values, byte slices
+// and values implementing Sizer. Size of other values is the size of their type.
+func (t *Tree[V]) Bytes() int {
+	return t.bytes
+}
+
+// elementBytes - internal function for estimation of memory of node with key and value
+func elementBytes[V constraints.Ordered](key V, value any) int {
+	return int(unsafe.Sizeof(node[V]{})) + keyBytes(key) + valueBytes(value)
+}
+
+// keyBytes - internal function for estimation of memory held by key outside of node
+func keyBytes[V constraints.Ordered](key V) int {
+	if s, ok := any(key).(Sizer); ok {
+		return s.Size()
+	}
+	if v := reflect.ValueOf(key); v.Kind() == reflect.String {
+		return v.Len()
+	}
+
+	return 0
+}
+
+// valueBytes - internal function for estimation of memory held by value outside of node
+func valueBytes(value any) int {
+	switch v := value.(type) {
+	case nil:
+		return 0
+	case Sizer:
+		return v.Size()
+	case string:
+		return len(v)
+	case []byte:
+		return len(v)
+	}
+
+	return int(reflect.TypeOf(value).Size())
+}
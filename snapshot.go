@@ -0,0 +1,140 @@
+package rbtree
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/exp/constraints"
+)
+
+// SnapshotTree is a goroutine-safe tree built on persistent nodes.
+// Writers are serialized by mutex and publish a new root atomically,
+// readers never lock: they work with the last published Snapshot
+type SnapshotTree[V constraints.Ordered] struct {
+	mu      sync.Mutex
+	current atomic.Pointer[Snapshot[V]]
+}
+
+// Snapshot is an immutable state of SnapshotTree.
+// Snapshot can be read from any goroutine without locking
+// and is not changed by later writes to SnapshotTree
+type Snapshot[V constraints.Ordered] struct {
+	root *pnode[V]
+	len  int
+}
+
+// NewSnapshotTree is a function for creation empty goroutine-safe tree with lock-free reads
+// - param should be `ordered type` (`int`, `string`, `float` etc)
+func NewSnapshotTree[V constraints.Ordered]() *SnapshotTree[V] {
+	t := &SnapshotTree[V]{}
+	t.current.Store(&Snapshot[V]{})
+
+	return t
+}
+
+// Insert is a function for inserting element into SnapshotTree.
+// If element with the same key exists, its value is replaced.
+// - param key should be `ordered type` (`int`, `string`, `float` etc.)
+// - param value can be any type
+func (t *SnapshotTree[V]) Insert(key V, value any) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := t.current.Load()
+	size := s.len
+	if pSearch(s.root, key) == nil {
+		size++
+	}
+
+	t.current.Store(&Snapshot[V]{
+		root: pInsert(s.root, element[V]{key: key, value: value}),
+		len:  size,
+	})
+}
+
+// Delete is a function for deleting element from SnapshotTree
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *SnapshotTree[V]) Delete(key V) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := t.current.Load()
+	if pSearch(s.root, key) == nil {
+		return
+	}
+
+	t.current.Store(&Snapshot[V]{
+		root: pDelete(s.root, key),
+		len:  s.len - 1,
+	})
+}
+
+// Snapshot is a function for getting current state of tree.
+// It never blocks and returned Snapshot is safe for reading without locks.
+func (t *SnapshotTree[V]) Snapshot() *Snapshot[V] {
+	return t.current.Load()
+}
+
+// Exists is a function for searching element in current state of tree.
+// If element exists in tree - return true, else - false
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *SnapshotTree[V]) Exists(key V) bool {
+	return t.Snapshot().Exists(key)
+}
+
+// GetValue is a function for searching element in current state of tree and returning value of this element
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *SnapshotTree[V]) GetValue(key V) (any, error) {
+	return t.Snapshot().GetValue(key)
+}
+
+// Len is a function for getting count of elements in snapshot.
+func (s *Snapshot[V]) Len() int {
+	return s.len
+}
+
+// Exists is a function for searching element in snapshot. If element exists - return true, else - false
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (s *Snapshot[V]) Exists(key V) bool {
+	return pSearch(s.root, key) != nil
+}
+
+// GetValue is a function for searching element in snapshot and returning value of this element
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (s *Snapshot[V]) GetValue(key V) (any, error) {
+	var result any
+	searchNode := pSearch(s.root, key)
+	if searchNode == nil {
+		return result, errors.New(fmt.Sprintf("element with key %v not found", key))
+	}
+
+	return searchNode.element.value, nil
+}
+
+// Min is a function for searching min element in snapshot (by key).
+func (s *Snapshot[V]) Min() V {
+	var result V
+	for n := s.root; n != nil; n = n.left {
+		result = n.element.key
+	}
+
+	return result
+}
+
+// Max is a function for searching max element in snapshot (by key).
+func (s *Snapshot[V]) Max() V {
+	var result V
+	for n := s.root; n != nil; n = n.right {
+		result = n.element.key
+	}
+
+	return result
+}
+
+// Ascend is a function for iterating over snapshot's elements in key order.
+// Iteration stops when fn returns false.
+func (s *Snapshot[V]) Ascend(fn func(key V, value any) bool) {
+	pAscend(s.root, fn)
+}
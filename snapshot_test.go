@@ -0,0 +1,141 @@
+package rbtree
+
+import (
+	"math/rand"
+	"reflect"
+	"sync"
+	"testing"
+
+	"golang.org/x/exp/constraints"
+)
+
+func TestSnapshotTree_InsertDelete(t1 *testing.T) {
+	t := NewSnapshotTree[int]()
+	want := map[int]int{}
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 2000; i++ {
+		key := rng.Intn(300)
+		if rng.Intn(3) == 0 {
+			t.Delete(key)
+			delete(want, key)
+		} else {
+			t.Insert(key, i)
+			want[key] = i
+		}
+
+		s := t.Snapshot()
+		checkPNodeProperties(t1, s.root)
+		if s.Len() != len(want) {
+			t1.Fatalf("Len() = %v, want %v", s.Len(), len(want))
+		}
+	}
+
+	for key, value := range want {
+		got, err := t.GetValue(key)
+		if err != nil || got != value {
+			t1.Errorf("GetValue(%v) = %v, %v, want %v", key, got, err, value)
+		}
+	}
+
+	prev := -1
+	t.Snapshot().Ascend(func(key int, value any) bool {
+		if key <= prev {
+			t1.Errorf("Ascend() key %v after %v", key, prev)
+		}
+		prev = key
+		return true
+	})
+}
+
+func TestSnapshot_Isolation(t1 *testing.T) {
+	t := NewSnapshotTree[int]()
+	t.Insert(22, 22)
+	t.Insert(8, 8)
+	t.Insert(4, 4)
+
+	s := t.Snapshot()
+	t.Insert(1, 1)
+	t.Insert(8, 80)
+	t.Delete(22)
+
+	var got []int
+	s.Ascend(func(key int, value any) bool {
+		got = append(got, value.(int))
+		return true
+	})
+	if want := []int{4, 8, 22}; !reflect.DeepEqual(got, want) {
+		t1.Errorf("old snapshot = %v, want %v", got, want)
+	}
+	if s.Min() != 4 || s.Max() != 22 {
+		t1.Errorf("old snapshot Min() = %v, Max() = %v", s.Min(), s.Max())
+	}
+
+	current := t.Snapshot()
+	if current.Min() != 1 || current.Max() != 8 || current.Exists(22) {
+		t1.Errorf("current snapshot Min() = %v, Max() = %v", current.Min(), current.Max())
+	}
+	if value, _ := current.GetValue(8); value != 80 {
+		t1.Errorf("current snapshot GetValue(8) = %v, want 80", value)
+	}
+}
+
+func TestSnapshotTree_ConcurrentReaders(t1 *testing.T) {
+	t := NewSnapshotTree[int]()
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			t.Insert(i, i)
+		}
+	}()
+
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				s := t.Snapshot()
+				count := 0
+				s.Ascend(func(key int, value any) bool {
+					count++
+					return true
+				})
+				if count != s.Len() {
+					t1.Errorf("snapshot has %v elements, Len() = %v", count, s.Len())
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// checkPNodeProperties checks red-black properties of persistent tree and returns its black height
+func checkPNodeProperties[V constraints.Ordered](t *testing.T, n *pnode[V]) int {
+	if n == nil {
+		return 1
+	}
+	if pIsRed(n) && (pIsRed(n.left) || pIsRed(n.right)) {
+		t.Fatalf("red node %v has red child", n.element.key)
+	}
+	if n.left != nil && n.left.element.key >= n.element.key ||
+		n.right != nil && n.right.element.key <= n.element.key {
+		t.Fatalf("node %v breaks key order", n.element.key)
+	}
+
+	if n.size != pSize(n.left)+1+pSize(n.right) {
+		t.Fatalf("node %v has size %v, want %v", n.element.key, n.size, pSize(n.left)+1+pSize(n.right))
+	}
+
+	left := checkPNodeProperties(t, n.left)
+	if right := checkPNodeProperties(t, n.right); left != right {
+		t.Fatalf("node %v has different black heights %v and %v", n.element.key, left, right)
+	}
+	if n.color == black {
+		left++
+	}
+
+	return left
+}
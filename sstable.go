@@ -0,0 +1,312 @@
+package rbtree
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+// sstableMagic is the last bytes of sorted string table
+const sstableMagic = "RBSSTBL1"
+
+// sstableBlockSize is min size of data block, block is finished by the first element which exceeds it
+const sstableBlockSize = 4096
+
+// sstableFooterLen is length of footer: index offset, index length, count of elements and magic
+const sstableFooterLen = 8 + 8 + 8 + len(sstableMagic)
+
+// WriteSSTable is a function for writing tree to w as sorted string table which can be read by SSTableReader.
+// Format: data blocks (elements in key order and CRC32 of block), sparse index (first key, offset and length
+// of every block and CRC32 of index) and footer (index offset, index length, count of elements and magic).
+// It returns count of written bytes.
+func (t *Tree[V]) WriteSSTable(w io.Writer) (int64, error) {
+	t.guard.startRead()
+	defer t.guard.endRead()
+
+	c := t.getCodecs()
+	cw := &checksumWriter{w: w, crc: crc32.NewIEEE()}
+	var block, index []byte
+	var blockOffset int64
+
+	flush := func() error {
+		if len(block) == 0 {
+			return nil
+		}
+		block = binary.BigEndian.AppendUint32(block, crc32.ChecksumIEEE(block))
+		index = binary.AppendUvarint(index, uint64(blockOffset))
+		index = binary.AppendUvarint(index, uint64(len(block)))
+		err := cw.write(block)
+		blockOffset = cw.n
+		block = block[:0]
+		return err
+	}
+
+	var err error
+	t.Ascend(func(key V, value any) bool {
+		if len(block) == 0 {
+			// index entry starts with the first key of block
+			var kb []byte
+			if kb, err = c.keys.AppendKey(nil, key); err != nil {
+				return false
+			}
+			index = binary.AppendUvarint(index, uint64(len(kb)))
+			index = append(index, kb...)
+		}
+		if block, err = appendElement(block, c, key, value); err != nil {
+			return false
+		}
+		if len(block) >= sstableBlockSize {
+			err = flush()
+		}
+		return err == nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		return cw.n, err
+	}
+
+	indexOffset := cw.n
+	index = binary.BigEndian.AppendUint32(index, crc32.ChecksumIEEE(index))
+	footer := binary.BigEndian.AppendUint64(nil, uint64(indexOffset))
+	footer = binary.BigEndian.AppendUint64(footer, uint64(len(index)))
+	footer = binary.BigEndian.AppendUint64(footer, uint64(t.size))
+	footer = append(footer, sstableMagic...)
+	if err := cw.write(index); err != nil {
+		return cw.n, err
+	}
+	err = cw.write(footer)
+
+	return cw.n, err
+}
+
+// SSTableReader is a reader of sorted string table written by WriteSSTable.
+// Only sparse index is kept in memory, data blocks are read from io.ReaderAt on every lookup.
+// SSTableReader is safe for concurrent use if its io.ReaderAt is
+type SSTableReader[V constraints.Ordered] struct {
+	r      io.ReaderAt
+	codecs *codecs[V]
+	blocks []sstableBlock[V]
+	count  int
+}
+
+// sstableBlock is the structure of index entry of data block
+type sstableBlock[V constraints.Ordered] struct {
+	first  V
+	offset int64
+	length int
+}
+
+// OpenSSTable is a function for opening sorted string table
+// - param r is source of table
+// - param size is length of table in bytes
+// - param keys is codec of keys, OrderedKeyCodec is used if it's nil
+// - param values is codec of values, GobValueCodec is used if it's nil
+func OpenSSTable[V constraints.Ordered](r io.ReaderAt, size int64, keys KeyCodec[V], values ValueCodec) (*SSTableReader[V], error) {
+	if size < int64(sstableFooterLen) {
+		return nil, errors.New("sorted string table is too short")
+	}
+	footer := make([]byte, sstableFooterLen)
+	if n, err := r.ReadAt(footer, size-int64(sstableFooterLen)); err != nil && !(err == io.EOF && n == len(footer)) {
+		return nil, err
+	}
+	if string(footer[24:]) != sstableMagic {
+		return nil, errors.New("invalid magic of sorted string table")
+	}
+
+	indexOffset := binary.BigEndian.Uint64(footer)
+	indexLen := binary.BigEndian.Uint64(footer[8:])
+	// bounds are checked without sums, which can overflow for crafted footer
+	limit := uint64(size) - uint64(sstableFooterLen)
+	if indexLen < 4 || indexOffset > limit || indexLen > limit-indexOffset {
+		return nil, errors.New("invalid index of sorted string table")
+	}
+	index, err := readChecked(r, int64(indexOffset), int(indexLen))
+	if err != nil {
+		return nil, err
+	}
+
+	t := &SSTableReader[V]{
+		r:      r,
+		codecs: newCodecs(keys, values),
+		count:  int(binary.BigEndian.Uint64(footer[16:])),
+	}
+
+	cr := &checksumReader{r: bytes.NewReader(index), crc: crc32.NewIEEE()}
+	for cr.n < int64(len(index)) {
+		kb, err := cr.readBytes()
+		if err != nil {
+			return nil, err
+		}
+		var b sstableBlock[V]
+		if b.first, err = t.codecs.keys.DecodeKey(kb); err != nil {
+			return nil, err
+		}
+		offset, err := binary.ReadUvarint(cr)
+		if err != nil {
+			return nil, err
+		}
+		length, err := binary.ReadUvarint(cr)
+		if err != nil {
+			return nil, err
+		}
+		if length < 4 || offset > indexOffset || length > indexOffset-offset {
+			return nil, errors.New("invalid block of sorted string table")
+		}
+		b.offset, b.length = int64(offset), int(length)
+		t.blocks = append(t.blocks, b)
+	}
+
+	return t, nil
+}
+
+// Len is a function for getting count of elements in table.
+func (t *SSTableReader[V]) Len() int {
+	return t.count
+}
+
+// GetValue is a function for searching element in table and returning value of this element
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *SSTableReader[V]) GetValue(key V) (any, error) {
+	var result any
+	found := false
+	err := t.scanBlock(t.blockOf(key), func(e element[V]) bool {
+		if e.key == key {
+			result, found = e.value, true
+		}
+		return e.key < key
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errors.New(fmt.Sprintf("element with key %v not found", key))
+	}
+
+	return result, nil
+}
+
+// Floor is a function for searching element with the greatest key <= key.
+// It returns error if there is no such element.
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *SSTableReader[V]) Floor(key V) (V, any, error) {
+	var result element[V]
+	found := false
+	err := t.scanBlock(t.blockOf(key), func(e element[V]) bool {
+		if e.key <= key {
+			result, found = e, true
+		}
+		return e.key < key
+	})
+	if err == nil && !found {
+		err = errors.New(fmt.Sprintf("element with key <= %v not found", key))
+	}
+
+	return result.key, result.value, err
+}
+
+// Ceiling is a function for searching element with the smallest key >= key.
+// It returns error if there is no such element.
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *SSTableReader[V]) Ceiling(key V) (V, any, error) {
+	var result element[V]
+	found := false
+	err := t.Scan(key, func(k V, value any) bool {
+		result, found = element[V]{key: k, value: value}, true
+		return false
+	})
+	if err == nil && !found {
+		err = errors.New(fmt.Sprintf("element with key >= %v not found", key))
+	}
+
+	return result.key, result.value, err
+}
+
+// Scan is a function for iterating over table's elements with keys >= lo in key order.
+// Iteration stops when fn returns false.
+// - param lo should be `ordered type` (`int`, `string`, `float` etc)
+func (t *SSTableReader[V]) Scan(lo V, fn func(key V, value any) bool) error {
+	i := t.blockOf(lo)
+	if i < 0 {
+		i = 0
+	}
+
+	for stopped := false; i < len(t.blocks) && !stopped; i++ {
+		err := t.scanBlock(i, func(e element[V]) bool {
+			if e.key < lo {
+				return true
+			}
+			stopped = !fn(e.key, e.value)
+			return !stopped
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ScanRange is a function for iterating over table's elements with keys in range [lo, hi) in key order.
+// Iteration stops when fn returns false.
+// - params lo and hi should be `ordered type` (`int`, `string`, `float` etc)
+func (t *SSTableReader[V]) ScanRange(lo, hi V, fn func(key V, value any) bool) error {
+	return t.Scan(lo, func(key V, value any) bool {
+		return key < hi && fn(key, value)
+	})
+}
+
+// blockOf - internal function for searching the last block with the first key <= key, -1 if there is no such block
+func (t *SSTableReader[V]) blockOf(key V) int {
+	return sort.Search(len(t.blocks), func(i int) bool { return key < t.blocks[i].first }) - 1
+}
+
+// scanBlock - internal function for reading block i and calling fn for its elements while fn returns true
+func (t *SSTableReader[V]) scanBlock(i int, fn func(e element[V]) bool) error {
+	if i < 0 || i >= len(t.blocks) {
+		return nil
+	}
+
+	b := t.blocks[i]
+	data, err := readChecked(t.r, b.offset, b.length)
+	if err != nil {
+		return err
+	}
+
+	r := &checksumReader{r: bytes.NewReader(data), crc: crc32.NewIEEE()}
+	for r.n < int64(len(data)) {
+		e, err := readElement(r, t.codecs)
+		if err != nil {
+			return err
+		}
+		if !fn(e) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// readChecked - internal function for reading length bytes at offset which are finished by CRC32 of previous bytes.
+// It returns bytes without CRC32.
+func readChecked(r io.ReaderAt, offset int64, length int) ([]byte, error) {
+	b := make([]byte, length)
+	// io.ReaderAt can return io.EOF with full read at the end of source
+	if n, err := r.ReadAt(b, offset); err != nil && !(err == io.EOF && n == length) {
+		return nil, err
+	}
+
+	data := b[:length-4]
+	if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(b[length-4:]) {
+		return nil, errors.New("checksum mismatch in sorted string table")
+	}
+
+	return data, nil
+}
@@ -0,0 +1,169 @@
+package rbtree
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSSTableReader(t1 *testing.T) {
+	t := New[int]()
+	for i := 0; i < 3000; i++ {
+		t.Insert(i*2, fmt.Sprintf("value %v", i))
+	}
+
+	var buf bytes.Buffer
+	n, err := t.WriteSSTable(&buf)
+	if err != nil || n != int64(buf.Len()) {
+		t1.Fatalf("WriteSSTable() = %v, %v", n, err)
+	}
+	r, err := OpenSSTable[int](bytes.NewReader(buf.Bytes()), n, nil, nil)
+	if err != nil {
+		t1.Fatalf("OpenSSTable() error = %v", err)
+	}
+	if len(r.blocks) < 2 || r.Len() != 3000 {
+		t1.Fatalf("table has %v blocks and %v elements", len(r.blocks), r.Len())
+	}
+
+	for _, key := range []int{0, 2, 1000, 5998} {
+		if value, err := r.GetValue(key); err != nil || value != fmt.Sprintf("value %v", key/2) {
+			t1.Errorf("GetValue(%v) = %v, %v", key, value, err)
+		}
+	}
+	for _, key := range []int{-1, 1, 5999} {
+		if _, err := r.GetValue(key); err == nil {
+			t1.Errorf("GetValue(%v) of missing key without error", key)
+		}
+	}
+
+	type boundCase struct {
+		key     int
+		want    int
+		wantErr bool
+	}
+	for _, tt := range []boundCase{{key: -5, wantErr: true}, {key: 0, want: 0}, {key: 7, want: 6}, {key: 10000, want: 5998}} {
+		if got, _, err := r.Floor(tt.key); (err != nil) != tt.wantErr || err == nil && got != tt.want {
+			t1.Errorf("Floor(%v) = %v, %v, want %v", tt.key, got, err, tt.want)
+		}
+	}
+	for _, tt := range []boundCase{{key: -5, want: 0}, {key: 7, want: 8}, {key: 5998, want: 5998}, {key: 5999, wantErr: true}} {
+		if got, _, err := r.Ceiling(tt.key); (err != nil) != tt.wantErr || err == nil && got != tt.want {
+			t1.Errorf("Ceiling(%v) = %v, %v, want %v", tt.key, got, err, tt.want)
+		}
+	}
+
+	var got []int
+	if err := r.ScanRange(1001, 1011, func(key int, value any) bool {
+		got = append(got, key)
+		return true
+	}); err != nil {
+		t1.Fatalf("ScanRange() error = %v", err)
+	}
+	if want := []int{1002, 1004, 1006, 1008, 1010}; !reflect.DeepEqual(got, want) {
+		t1.Errorf("ScanRange() = %v, want %v", got, want)
+	}
+
+	count := 0
+	r.Scan(-100, func(key int, value any) bool {
+		count++
+		return true
+	})
+	if count != 3000 {
+		t1.Errorf("Scan() visited %v elements, want 3000", count)
+	}
+}
+
+func TestSSTableReader_errors(t1 *testing.T) {
+	var buf bytes.Buffer
+	getTree([]int{22, 8, 4}).WriteSSTable(&buf)
+	data := buf.Bytes()
+
+	if _, err := OpenSSTable[int](bytes.NewReader(data[:10]), 10, nil, nil); err == nil {
+		t1.Errorf("OpenSSTable() of short table without error")
+	}
+
+	corrupted := append([]byte(nil), data...)
+	corrupted[1] ^= 0xff
+	r, err := OpenSSTable[int](bytes.NewReader(corrupted), int64(len(corrupted)), nil, nil)
+	if err != nil {
+		t1.Fatalf("OpenSSTable() error = %v", err)
+	}
+	if _, err := r.GetValue(4); err == nil {
+		t1.Errorf("GetValue() from corrupted block without error")
+	}
+
+	var empty bytes.Buffer
+	New[int]().WriteSSTable(&empty)
+	r, err = OpenSSTable[int](bytes.NewReader(empty.Bytes()), int64(empty.Len()), nil, nil)
+	if err != nil {
+		t1.Fatalf("OpenSSTable() of empty table error = %v", err)
+	}
+	if _, _, err := r.Ceiling(0); err == nil {
+		t1.Errorf("Ceiling() in empty table without error")
+	}
+}
+
+func TestOpenSSTable_overflowingFooter(t1 *testing.T) {
+	var buf bytes.Buffer
+	getTree([]int{22, 8, 4}).WriteSSTable(&buf)
+	data := buf.Bytes()
+	footer := len(data) - sstableFooterLen
+
+	tests := []struct {
+		name                  string
+		indexOffset, indexLen uint64
+	}{
+		// sums of offset and length wrap around to small numbers
+		{name: "huge offset", indexOffset: math.MaxUint64 - 7, indexLen: 16},
+		{name: "huge length", indexOffset: 16, indexLen: math.MaxUint64 - 7},
+		{name: "offset after index", indexOffset: uint64(footer), indexLen: 4},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			corrupted := append([]byte(nil), data...)
+			binary.BigEndian.PutUint64(corrupted[footer:], tt.indexOffset)
+			binary.BigEndian.PutUint64(corrupted[footer+8:], tt.indexLen)
+			if _, err := OpenSSTable[int](bytes.NewReader(corrupted), int64(len(corrupted)), nil, nil); err == nil {
+				t1.Errorf("OpenSSTable() with index at %v of %v bytes without error", tt.indexOffset, tt.indexLen)
+			}
+		})
+	}
+}
+
+// eofReaderAt returns io.EOF with full read at the end of data, as io.ReaderAt is allowed to
+type eofReaderAt []byte
+
+func (r eofReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := bytes.NewReader(r).ReadAt(p, off)
+	if err == nil && off+int64(n) == int64(len(r)) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+func TestOpenSSTable_eofWithFullRead(t1 *testing.T) {
+	var buf bytes.Buffer
+	getTree([]int{22, 8, 4}).WriteSSTable(&buf)
+	data := buf.Bytes()
+
+	r, err := OpenSSTable[int](eofReaderAt(data), int64(len(data)), nil, nil)
+	if err != nil {
+		t1.Fatalf("OpenSSTable() error = %v", err)
+	}
+	if value, err := r.GetValue(8); err != nil || value != 8 {
+		t1.Errorf("GetValue() = %v, %v", value, err)
+	}
+
+	// the only data block is at the end of source
+	block := r.blocks[0]
+	if _, err := readChecked(eofReaderAt(data[:block.length]), 0, block.length); err != nil {
+		t1.Errorf("readChecked() of the last block error = %v", err)
+	}
+	if _, err := readChecked(eofReaderAt(data[:block.length-1]), 0, block.length); err == nil {
+		t1.Errorf("readChecked() of short block without error")
+	}
+}
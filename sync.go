@@ -0,0 +1,242 @@
+package rbtree
+
+import (
+	"errors"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/exp/constraints"
+)
+
+// syncRangeBatch is max count of elements which Range copies under read lock at once
+const syncRangeBatch = 64
+
+// SyncTree is a goroutine-safe wrapper of Tree.
+// All reads are done under read lock and all writes under write lock,
+// so compound operations (CompareAndSwap, LoadOrStore etc.) are atomic
+type SyncTree[V constraints.Ordered] struct {
+	mu   sync.RWMutex
+	tree *Tree[V]
+}
+
+// NewSyncTree is a function for creation empty goroutine-safe tree
+// - param should be `ordered type` (`int`, `string`, `float` etc)
+func NewSyncTree[V constraints.Ordered]() *SyncTree[V] {
+	return &SyncTree[V]{
+		tree: New[V](),
+	}
+}
+
+// Insert is a function for inserting element into tree.
+// If element with the same key exists, its value is replaced.
+// - param key should be `ordered type` (`int`, `string`, `float` etc.)
+// - param value can be any type
+func (t *SyncTree[V]) Insert(key V, value any) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.tree.Insert(key, value)
+}
+
+// Delete is a function for deleting element from tree
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *SyncTree[V]) Delete(key V) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.tree.Delete(key)
+}
+
+// Exists is a function for searching element in tree. If element exists in tree - return true, else - false
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *SyncTree[V]) Exists(key V) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.tree.Exists(key)
+}
+
+// GetValue is a function for searching element in tree and returning value of this element
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *SyncTree[V]) GetValue(key V) (any, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.tree.GetValue(key)
+}
+
+// Min is a function for searching min element in tree (by key).
+func (t *SyncTree[V]) Min() V {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.tree.Min()
+}
+
+// Max is a function for searching max element in tree (by key).
+func (t *SyncTree[V]) Max() V {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.tree.Max()
+}
+
+// CompareAndSwap is a function for replacing value of element if its current value is equal to old.
+// It returns true if value was replaced. Old value must be of comparable type.
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *SyncTree[V]) CompareAndSwap(key V, old, new any) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	n := t.tree.search(key)
+	if n == nil || n.element.value != old {
+		return false
+	}
+	t.tree.put(key, new)
+
+	return true
+}
+
+// CompareAndDelete is a function for deleting element if its current value is equal to old.
+// It returns true if element was deleted. Old value must be of comparable type.
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *SyncTree[V]) CompareAndDelete(key V, old any) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	n := t.tree.search(key)
+	if n == nil || n.element.value != old {
+		return false
+	}
+	t.tree.remove(key)
+
+	return true
+}
+
+// LoadOrStore is a function for getting value of existing element or inserting element with value.
+// It returns existing value and true if element was found, else - inserted value and false.
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *SyncTree[V]) LoadOrStore(key V, value any) (any, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if n := t.tree.search(key); n != nil {
+		return n.element.value, true
+	}
+	t.tree.put(key, value)
+
+	return value, false
+}
+
+// LoadAndDelete is a function for deleting element and returning its value.
+// It returns true if element existed.
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *SyncTree[V]) LoadAndDelete(key V) (any, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.tree.remove(key)
+}
+
+// Len is a function for getting count of elements in tree.
+func (t *SyncTree[V]) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.tree.Len()
+}
+
+// Ascend is a function for iterating over tree's elements in key order under read lock.
+// Iteration stops when fn returns false.
+// fn must not call any method of SyncTree: read lock isn't recursive, so even reads
+// (Exists, GetValue etc.) deadlock when a writer is waiting. Use Range if fn needs the tree.
+// Writers are blocked until iteration is finished, use Range for long callbacks.
+func (t *SyncTree[V]) Ascend(fn func(key V, value any) bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	t.tree.Ascend(fn)
+}
+
+// AscendRange is a function for iterating over tree's elements with keys in range [lo, hi) in key order
+// under read lock. Iteration stops when fn returns false.
+// fn must not call any method of SyncTree (see Ascend), use Range if fn needs the tree.
+// - params lo and hi should be `ordered type` (`int`, `string`, `float` etc)
+func (t *SyncTree[V]) AscendRange(lo, hi V, fn func(key V, value any) bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	t.tree.AscendRange(lo, hi, fn)
+}
+
+// Split is a function for moving elements with keys >= key to new goroutine-safe tree (see Tree.Split).
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *SyncTree[V]) Split(key V) *SyncTree[V] {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return &SyncTree[V]{tree: t.tree.Split(key)}
+}
+
+// Join is a function for moving all elements of other tree to tree (see Tree.Join).
+// Both trees are locked in order of their addresses, so concurrent joins of the same trees don't deadlock.
+func (t *SyncTree[V]) Join(other *SyncTree[V]) error {
+	if other == t {
+		return errors.New("tree can't be joined with itself")
+	}
+
+	first, second := t, other
+	if uintptr(unsafe.Pointer(other)) < uintptr(unsafe.Pointer(t)) {
+		first, second = other, t
+	}
+	first.mu.Lock()
+	defer first.mu.Unlock()
+	second.mu.Lock()
+	defer second.mu.Unlock()
+
+	return t.tree.Join(other.tree)
+}
+
+// Range is a function for iterating over tree's elements in key order without holding locks during fn calls.
+// Elements are copied by small batches under read lock, so fn can call any method of SyncTree.
+// Range doesn't correspond to any consistent state of tree: every key is visited at most once,
+// but elements changed during iteration may be visited with old or new values or not visited.
+// Iteration stops when fn returns false.
+func (t *SyncTree[V]) Range(fn func(key V, value any) bool) {
+	batch := t.copyBatch(make([]element[V], 0, syncRangeBatch), nil)
+	for len(batch) > 0 {
+		for _, e := range batch {
+			if !fn(e.key, e.value) {
+				return
+			}
+		}
+		if len(batch) < syncRangeBatch {
+			return
+		}
+
+		last := batch[len(batch)-1].key
+		batch = t.copyBatch(batch[:0], &last)
+	}
+}
+
+// copyBatch - internal function for copying next batch of elements with keys greater than after
+// (from the beginning of tree if after is nil)
+func (t *SyncTree[V]) copyBatch(batch []element[V], after *V) []element[V] {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var n *node[V]
+	switch {
+	case after != nil:
+		n = t.tree.ceiling(*after, true)
+	case t.tree.root != t.tree.nilNode:
+		n = t.tree.min(t.tree.root)
+	}
+
+	t.tree.ascendFrom(n, func(key V, value any) bool {
+		batch = append(batch, element[V]{key: key, value: value})
+		return len(batch) < syncRangeBatch
+	})
+
+	return batch
+}
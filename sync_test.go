@@ -0,0 +1,152 @@
+package rbtree
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestSyncTree_CompoundOperations(t1 *testing.T) {
+	t := NewSyncTree[int]()
+	t.Insert(8, "a")
+
+	if t.CompareAndSwap(8, "b", "c") {
+		t1.Errorf("CompareAndSwap() with wrong old value = true")
+	}
+	if !t.CompareAndSwap(8, "a", "b") {
+		t1.Errorf("CompareAndSwap() with right old value = false")
+	}
+	if t.CompareAndSwap(4, nil, "b") {
+		t1.Errorf("CompareAndSwap() for missing key = true")
+	}
+
+	if value, loaded := t.LoadOrStore(8, "x"); !loaded || value != "b" {
+		t1.Errorf("LoadOrStore() existing = %v, %v, want b, true", value, loaded)
+	}
+	if value, loaded := t.LoadOrStore(4, "x"); loaded || value != "x" {
+		t1.Errorf("LoadOrStore() missing = %v, %v, want x, false", value, loaded)
+	}
+
+	if t.CompareAndDelete(4, "y") {
+		t1.Errorf("CompareAndDelete() with wrong old value = true")
+	}
+	if !t.CompareAndDelete(4, "x") || t.Exists(4) {
+		t1.Errorf("CompareAndDelete() with right old value didn't delete element")
+	}
+
+	if value, loaded := t.LoadAndDelete(8); !loaded || value != "b" {
+		t1.Errorf("LoadAndDelete() = %v, %v, want b, true", value, loaded)
+	}
+	if value, loaded := t.LoadAndDelete(8); loaded || value != nil {
+		t1.Errorf("LoadAndDelete() second time = %v, %v, want nil, false", value, loaded)
+	}
+}
+
+func TestSyncTree_Range(t1 *testing.T) {
+	t := NewSyncTree[int]()
+	var want []int
+	for i := 0; i < 3*syncRangeBatch+5; i++ {
+		t.Insert(i, i)
+		want = append(want, i)
+	}
+
+	var got []int
+	t.Range(func(key int, value any) bool {
+		got = append(got, key)
+		t.Delete(key) // fn can change tree
+		return true
+	})
+	if !reflect.DeepEqual(got, want) {
+		t1.Errorf("Range() visited %v keys, want %v", len(got), len(want))
+	}
+	if t.Exists(0) {
+		t1.Errorf("elements weren't deleted in Range()")
+	}
+
+	t.Insert(1, 1)
+	t.Insert(2, 2)
+	count := 0
+	t.Range(func(key int, value any) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t1.Errorf("Range() didn't stop, %v calls", count)
+	}
+}
+
+func TestSyncTree_Concurrent(t1 *testing.T) {
+	t := NewSyncTree[int]()
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				for {
+					value, _ := t.LoadOrStore(i, 0)
+					if t.CompareAndSwap(i, value, value.(int)+1) {
+						break
+					}
+				}
+				t.Ascend(func(key int, value any) bool { return key < 10 })
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < 200; i++ {
+		if value, _ := t.GetValue(i); value != 8 {
+			t1.Errorf("GetValue(%v) = %v, want 8", i, value)
+		}
+	}
+	if t.Min() != 0 || t.Max() != 199 {
+		t1.Errorf("Min() = %v, Max() = %v", t.Min(), t.Max())
+	}
+}
+
+func TestSyncTree_SplitJoin(t1 *testing.T) {
+	t := NewSyncTree[int]()
+	for i := 0; i < 10; i++ {
+		t.Insert(i, i)
+	}
+
+	var got []int
+	t.AscendRange(3, 6, func(key int, value any) bool {
+		got = append(got, key)
+		return true
+	})
+	if !reflect.DeepEqual(got, []int{3, 4, 5}) {
+		t1.Errorf("AscendRange(3, 6) = %v, want [3 4 5]", got)
+	}
+
+	right := t.Split(5)
+	if t.Len() != 5 || right.Len() != 5 || right.Min() != 5 {
+		t1.Errorf("Split(5) = %v and %v elements, min of right %v", t.Len(), right.Len(), right.Min())
+	}
+
+	// concurrent joins in opposite directions don't deadlock:
+	// right.Join(t) fails if it's the first, else it moves all elements to empty right tree
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		errs[0] = t.Join(right)
+	}()
+	go func() {
+		defer wg.Done()
+		errs[1] = right.Join(t)
+	}()
+	wg.Wait()
+	if errs[0] != nil {
+		t1.Errorf("Join() error = %v", errs[0])
+	}
+	if t.Len()+right.Len() != 10 || t.Len() != 0 && right.Len() != 0 {
+		t1.Errorf("Len() after Join = %v and %v, want all 10 elements in one tree", t.Len(), right.Len())
+	}
+
+	if err := t.Join(t); err == nil {
+		t1.Errorf("Join() with itself error = nil")
+	}
+}
@@ -3,6 +3,7 @@ package rbtree
 import (
 	"errors"
 	"fmt"
+	"math/bits"
 
 	"golang.org/x/exp/constraints"
 )
@@ -10,80 +11,63 @@ import (
 type Tree[V constraints.Ordered] struct {
 	root    *node[V]
 	nilNode *node[V]
+	size    int
+	bytes   int
+	guard   guard
+	codecs  *codecs[V]
+	merkle  bool            // nodes have merkle hashes
+	aug     augmentation[V] // nodes have aggregates of AggregateTree
 }
 
 // New is a function for creation empty tree
 // - param should be `ordered type` (`int`, `string`, `float` etc)
-func New[V constraints.Ordered]() *Tree[V] {
+// - param opts are optional settings of tree (WithAccessCheck etc)
+func New[V constraints.Ordered](opts ...Option) *Tree[V] {
 	nilNode := &node[V]{
 		color: black,
 	}
 
+	o := newOptions(opts)
+
 	return &Tree[V]{
 		root:    nilNode,
 		nilNode: nilNode,
+		guard:   guard{enabled: o.checkAccess},
+		merkle:  o.merkle,
 	}
 }
 
 // NewWithElement is a function for creation tree with one element
 // - param should be `ordered type` (`int`, `string`, `float` etc)
-func NewWithElement[V constraints.Ordered](key V, value any) *Tree[V] {
-	nilNode := &node[V]{
-		color: black,
-	}
+// - param opts are optional settings of tree (WithAccessCheck etc)
+func NewWithElement[V constraints.Ordered](key V, value any, opts ...Option) *Tree[V] {
+	t := New[V](opts...)
+	t.put(key, value)
 
-	return &Tree[V]{
-		root: &node[V]{
-			element: element[V]{
-				key:   key,
-				value: value,
-			},
-			color:  black,
-			left:   nilNode,
-			right:  nilNode,
-			parent: nilNode,
-		},
-		nilNode: nilNode,
-	}
+	return t
 }
 
-// Insert is a function for inserting element into Tree
+// Insert is a function for inserting element into Tree.
+// If element with the same key exists, its value is replaced.
 // - param key should be `ordered type` (`int`, `string`, `float` etc.)
 // - param value can be any type
 func (t *Tree[V]) Insert(key V, value any) {
-	newNode := t.getNewNode(key, value)
-
-	if t.root == t.nilNode {
-		t.root = newNode
-		t.insertFixup(newNode)
-
-		return
-	}
+	t.guard.startWrite()
+	defer t.guard.endWrite()
 
-	current := t.root
-	for {
-		if key < current.element.key {
-			if current.left == t.nilNode {
-				current.left = newNode
-				newNode.parent = current
-				break
-			}
-			current = current.left
-			continue
-		}
+	t.put(key, value)
+}
 
-		if current.right == t.nilNode {
-			current.right = newNode
-			newNode.parent = current
-			break
-		}
-		current = current.right
-	}
-	t.insertFixup(newNode)
+// Len is a function for getting count of elements in tree.
+func (t *Tree[V]) Len() int {
+	return t.size
 }
 
 // Min is a function for searching min element in tree (by key).
 func (t *Tree[V]) Min() V {
+	t.guard.startRead()
+	defer t.guard.endRead()
+
 	n := t.root
 	if n == t.nilNode {
 		return t.nilNode.element.key
@@ -98,6 +82,9 @@ func (t *Tree[V]) Min() V {
 
 // Max is a function for searching max element in tree (by key).
 func (t *Tree[V]) Max() V {
+	t.guard.startRead()
+	defer t.guard.endRead()
+
 	n := t.root
 	if n == t.nilNode {
 		return t.nilNode.element.key
@@ -113,14 +100,20 @@ func (t *Tree[V]) Max() V {
 // Exists is a function for searching element in node. If element exists in tree - return true, else - false
 // - param key should be `ordered type` (`int`, `string`, `float` etc)
 func (t *Tree[V]) Exists(key V) bool {
-	return search(t.root, key) != nil
+	t.guard.startRead()
+	defer t.guard.endRead()
+
+	return t.search(key) != nil
 }
 
 // GetValue is a function for searching element in node and returning value of this element
 // - param key should be `ordered type` (`int`, `string`, `float` etc)
 func (t *Tree[V]) GetValue(key V) (any, error) {
+	t.guard.startRead()
+	defer t.guard.endRead()
+
 	var result any
-	searchNode := search(t.root, key)
+	searchNode := t.search(key)
 	if searchNode == nil {
 		return result, errors.New(fmt.Sprintf("element with key %v not found", key))
 	}
@@ -131,15 +124,299 @@ func (t *Tree[V]) GetValue(key V) (any, error) {
 // Delete is a function for deleting node in rbtree
 // - param key should be `ordered type` (`int`, `string`, `float` etc)
 func (t *Tree[V]) Delete(key V) {
-	z := search(t.root, key)
-	if z == nil {
+	t.guard.startWrite()
+	defer t.guard.endWrite()
+
+	t.remove(key)
+}
+
+// Ascend is a function for iterating over tree's elements in key order.
+// Iteration stops when fn returns false.
+func (t *Tree[V]) Ascend(fn func(key V, value any) bool) {
+	t.guard.startRead()
+	defer t.guard.endRead()
+
+	if t.root == t.nilNode {
 		return
 	}
-	yOriginalColor, x := t.deleteNode(z)
+	t.ascendFrom(t.min(t.root), fn)
+}
+
+// AscendRange is a function for iterating over tree's elements with keys in range [lo, hi) in key order.
+// Iteration stops when fn returns false.
+// - params lo and hi should be `ordered type` (`int`, `string`, `float` etc)
+func (t *Tree[V]) AscendRange(lo, hi V, fn func(key V, value any) bool) {
+	t.guard.startRead()
+	defer t.guard.endRead()
+
+	t.ascendFrom(t.ceiling(lo, false), func(key V, value any) bool {
+		return key < hi && fn(key, value)
+	})
+}
+
+// Split is a function for moving elements with keys >= key to new tree.
+// Tree keeps elements with keys < key. Both trees are rebuilt in O(n).
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *Tree[V]) Split(key V) *Tree[V] {
+	t.guard.startWrite()
+	defer t.guard.endWrite()
+
+	elements := t.elements()
+	i := 0
+	for i < len(elements) && elements[i].key < key {
+		i++
+	}
+
+	right := New[V]()
+	right.guard.enabled, right.codecs, right.merkle, right.aug = t.guard.enabled, t.codecs, t.merkle, t.aug
+	right.build(elements[i:])
+	t.build(elements[:i])
+
+	return right
+}
+
+// Join is a function for moving all elements of other tree to tree.
+// All keys of other tree should be greater than keys of tree, other tree becomes empty.
+// Tree is rebuilt in O(n).
+func (t *Tree[V]) Join(other *Tree[V]) error {
+	t.guard.startWrite()
+	defer t.guard.endWrite()
+	other.guard.startWrite()
+	defer other.guard.endWrite()
+
+	if other.size == 0 {
+		return nil
+	}
+	if t.size > 0 && other.min(other.root).element.key <= t.max(t.root).element.key {
+		return errors.New(fmt.Sprintf("keys of joined tree should be greater than %v", t.max(t.root).element.key))
+	}
+
+	t.build(append(t.elements(), other.elements()...))
+	other.build(nil)
+
+	return nil
+}
+
+// put - internal function for inserting or replacing element.
+// It returns previous value of element and true if element existed.
+func (t *Tree[V]) put(key V, value any) (any, bool) {
+	if t.root == t.nilNode {
+		t.root = t.getNewNode(key, value)
+		t.insertFixup(t.root)
+		t.size++
+		t.bytes += elementBytes(key, value)
+
+		return nil, false
+	}
+
+	current := t.root
+	for {
+		t.push(current)
+		if key == current.element.key {
+			old := current.element.value
+			current.element.value = value
+			t.bytes += valueBytes(value) - valueBytes(old)
+			if t.merkle {
+				current.ext.merkle.self = t.elementHash(key, value)
+			}
+			t.pullPath(current)
+			return old, true
+		}
+
+		if key < current.element.key {
+			if current.left == t.nilNode {
+				current.left = t.getNewNode(key, value)
+				current.left.parent = current
+				t.pullPath(current)
+				t.insertFixup(current.left)
+				t.size++
+				t.bytes += elementBytes(key, value)
+				return nil, false
+			}
+			current = current.left
+			continue
+		}
+
+		if current.right == t.nilNode {
+			current.right = t.getNewNode(key, value)
+			current.right.parent = current
+			t.pullPath(current)
+			t.insertFixup(current.right)
+			t.size++
+			t.bytes += elementBytes(key, value)
+			return nil, false
+		}
+		current = current.right
+	}
+}
+
+// remove - internal function for deleting element.
+// It returns value of deleted element and true if element existed.
+func (t *Tree[V]) remove(key V) (any, bool) {
+	z := t.search(key)
+	if z == nil {
+		return nil, false
+	}
+	t.removeNode(z)
+
+	return z.element.value, true
+}
+
+// removeNode - internal function for deleting node z from tree
+func (t *Tree[V]) removeNode(z *node[V]) {
+	t.unlinkNode(z)
+	t.bytes -= elementBytes(z.element.key, z.element.value)
+}
+
+// unlinkNode - internal function for deleting node z from tree without byte accounting
+func (t *Tree[V]) unlinkNode(z *node[V]) {
+	if z.left != t.nilNode && z.right != t.nilNode {
+		t.pushPath(t.min(z.right))
+	} else {
+		t.pushPath(z)
+	}
+	yOriginalColor, x, parent := t.deleteNode(z)
+	t.pullPath(parent)
 
 	if yOriginalColor == black {
-		t.deleteFixup(x)
+		t.deleteFixup(x, parent)
+	}
+	t.size--
+}
+
+// search - internal function for searching node by key. It returns nil if node doesn't exist.
+func (t *Tree[V]) search(key V) *node[V] {
+	n := t.root
+	for n != t.nilNode && key != n.element.key {
+		if key < n.element.key {
+			n = n.left
+			continue
+		}
+		n = n.right
+	}
+
+	if n == t.nilNode {
+		return nil
+	}
+
+	return n
+}
+
+// ceiling - internal function for searching node with the smallest key >= key
+// (> key if strict is true). It returns nil if node doesn't exist.
+func (t *Tree[V]) ceiling(key V, strict bool) *node[V] {
+	var result *node[V]
+	n := t.root
+	for n != t.nilNode {
+		if key < n.element.key || !strict && key == n.element.key {
+			result = n
+			n = n.left
+			continue
+		}
+		n = n.right
+	}
+
+	return result
+}
+
+// floor - internal function for searching node with the greatest key <= key
+// (< key if strict is true). It returns nil if node doesn't exist.
+func (t *Tree[V]) floor(key V, strict bool) *node[V] {
+	var result *node[V]
+	n := t.root
+	for n != t.nilNode {
+		if key > n.element.key || !strict && key == n.element.key {
+			result = n
+			n = n.right
+			continue
+		}
+		n = n.left
+	}
+
+	return result
+}
+
+// ascendFrom - internal function for iterating from node n in key order while fn returns true
+func (t *Tree[V]) ascendFrom(n *node[V], fn func(key V, value any) bool) {
+	for n != nil && fn(n.element.key, n.element.value) {
+		n = t.successor(n)
+	}
+}
+
+// elements - internal function for copying all elements of tree in key order
+func (t *Tree[V]) elements() []element[V] {
+	elements := make([]element[V], 0, t.size)
+	if t.root == t.nilNode {
+		return elements
+	}
+	t.ascendFrom(t.min(t.root), func(key V, value any) bool {
+		elements = append(elements, element[V]{key: key, value: value})
+		return true
+	})
+
+	return elements
+}
+
+// build - internal function for replacing all elements of tree by elements sorted by key in O(n).
+// Tree is perfectly balanced: all nodes are black except nodes of incomplete last level which are red.
+func (t *Tree[V]) build(elements []element[V]) {
+	redDepth := bits.Len(uint(len(elements)+1)) - 1
+	t.root = t.buildNode(elements, t.nilNode, 0, redDepth)
+	t.size = len(elements)
+	t.bytes = 0
+	for _, e := range elements {
+		t.bytes += elementBytes(e.key, e.value)
+	}
+}
+
+func (t *Tree[V]) buildNode(elements []element[V], parent *node[V], depth, redDepth int) *node[V] {
+	if len(elements) == 0 {
+		return t.nilNode
+	}
+
+	mid := len(elements) / 2
+	n := t.getNewNode(elements[mid].key, elements[mid].value)
+	n.parent = parent
+	if depth != redDepth {
+		n.color = black
+	}
+	n.left = t.buildNode(elements[:mid], n, depth+1, redDepth)
+	n.right = t.buildNode(elements[mid+1:], n, depth+1, redDepth)
+	t.pull(n)
+
+	return n
+}
+
+// successor - internal function for searching next node in key order. It returns nil for max node.
+func (t *Tree[V]) successor(n *node[V]) *node[V] {
+	if t.hasRightChild(n) {
+		return t.min(n.right)
+	}
+
+	for !t.isRoot(n) && isRightChild(n) {
+		n = n.parent
+	}
+	if t.isRoot(n) {
+		return nil
+	}
+
+	return n.parent
+}
+
+// predecessor - internal function for searching previous node in key order. It returns nil for min node.
+func (t *Tree[V]) predecessor(n *node[V]) *node[V] {
+	if t.hasLeftChild(n) {
+		return t.max(n.left)
+	}
+
+	for !t.isRoot(n) && isLeftChild(n) {
+		n = n.parent
+	}
+	if t.isRoot(n) {
+		return nil
 	}
+
+	return n.parent
 }
 
 // leftRotate - internal function for left rotating in rbtree
@@ -149,6 +426,8 @@ func (t *Tree[V]) leftRotate(x *node[V]) {
 	}
 
 	y := x.right
+	t.push(x)
+	t.push(y)
 	x.right = y.left
 
 	if t.hasLeftChild(y) {
@@ -167,6 +446,8 @@ func (t *Tree[V]) leftRotate(x *node[V]) {
 
 	y.left = x
 	x.parent = y
+	t.pull(x)
+	t.pull(y)
 }
 
 // rightRotate - internal function for right rotating in rbtree
@@ -176,6 +457,8 @@ func (t *Tree[V]) rightRotate(y *node[V]) {
 	}
 
 	x := y.left
+	t.push(y)
+	t.push(x)
 	y.left = x.right
 
 	if t.hasRightChild(x) {
@@ -195,10 +478,13 @@ func (t *Tree[V]) rightRotate(y *node[V]) {
 
 	x.right = y
 	y.parent = x
+	t.pull(y)
+	t.pull(x)
 }
 
-// insertFixup function calls after insert node to rbtree for recovery of rbtree's properties
-func (t *Tree[V]) insertFixup(z *node[V]) {
+// insertFixup function calls after insert node to rbtree for recovery of rbtree's properties.
+// It returns true if red root was recolored, i.e. black height of tree is increased by one
+func (t *Tree[V]) insertFixup(z *node[V]) bool {
 	for z.parent != t.nilNode && z.parent.color == red {
 		if isLeftChild(z.parent) {
 			y := z.parent.parent.right
@@ -230,29 +516,34 @@ func (t *Tree[V]) insertFixup(z *node[V]) {
 		recolorForInsertCase3(z)
 		t.leftRotate(z.parent.parent)
 	}
+	grown := t.root.color == red
 	t.root.color = black
+
+	return grown
 }
 
-// transplant - internal function for substitution u node to v node
+// transplant - internal function for substitution u node to v node.
+// Parent of nilNode isn't changed: nilNode can be shared by trees
 func (t *Tree[V]) transplant(u, v *node[V]) {
-	if t.isRoot(u) {
+	parent := u.parent
+	switch {
+	case t.isRoot(u):
 		t.root = v
-		v.parent = t.nilNode
-		return
+	case isLeftChild(u):
+		parent.left = v
+	default:
+		parent.right = v
 	}
 
-	if isLeftChild(u) {
-		u.parent.left = v
-		v.parent = u.parent
-		return
+	if v != t.nilNode {
+		v.parent = parent
 	}
-
-	u.parent.right = v
-	v.parent = u.parent
 }
 
-// deleteNode - internal function for deleting node in rbtree
-func (t *Tree[V]) deleteNode(z *node[V]) (color, *node[V]) {
+// deleteNode - internal function for deleting node in rbtree.
+// It returns original color of removed position, node x which took it and parent of x
+// (x can be nilNode, which has no parent).
+func (t *Tree[V]) deleteNode(z *node[V]) (color, *node[V], *node[V]) {
 	var yOriginalColor color
 	y := z
 	yOriginalColor = y.color
@@ -261,20 +552,22 @@ func (t *Tree[V]) deleteNode(z *node[V]) (color, *node[V]) {
 	if z.left == t.nilNode {
 		x = z.right
 		t.transplant(z, z.right)
-		return yOriginalColor, x
+		return yOriginalColor, x, z.parent
 	}
 
 	if z.right == t.nilNode {
 		x = z.left
 		t.transplant(z, z.left)
-		return yOriginalColor, x
+		return yOriginalColor, x, z.parent
 	}
 
 	y = t.min(z.right)
 	yOriginalColor = y.color
 	x = y.right
 
+	xParent := y
 	if y.parent != z {
+		xParent = y.parent
 		t.transplant(y, y.right)
 		y.right = z.right
 		y.right.parent = y
@@ -285,59 +578,63 @@ func (t *Tree[V]) deleteNode(z *node[V]) (color, *node[V]) {
 	y.left.parent = y
 	y.color = z.color
 
-	return yOriginalColor, x
+	return yOriginalColor, x, xParent
 }
 
-func (t *Tree[V]) deleteFixup(x *node[V]) {
+// deleteFixup - internal function for recovery of rbtree's properties after deleting,
+// parent is parent of x (x can be nilNode)
+func (t *Tree[V]) deleteFixup(x, parent *node[V]) {
 	var w *node[V]
 	for x != t.root && x.color == black {
-		if isLeftChild(x) {
-			w = x.parent.right
-			if t.recolorAndRotateCase1(x.parent, w) {
+		if x == parent.left {
+			w = parent.right
+			if t.recolorAndRotateCase1(parent, w) {
 				continue
 			}
 			if isBlack(w.left) && isBlack(w.right) {
 				w.color = red
-				x = x.parent
+				x, parent = parent, parent.parent
 				continue
 			}
 			if isBlack(w.right) {
 				w.color = red
 				w.left.color = black
 				t.rightRotate(w)
-				w = x.parent.right
+				w = parent.right
 			}
-			w.color = x.parent.color
-			x.parent.color = black
+			w.color = parent.color
+			parent.color = black
 			w.right.color = black
-			t.leftRotate(x.parent)
+			t.leftRotate(parent)
 			x = t.root
 			continue
 		}
 
-		w = x.parent.left
-		if t.recolorAndRotateCase1(x.parent, w) {
+		w = parent.left
+		if t.recolorAndRotateCase1(parent, w) {
 			continue
 		}
 		if isBlack(w.left) && isBlack(w.right) {
 			w.color = red
-			x = x.parent
+			x, parent = parent, parent.parent
 			continue
 		}
 		if isBlack(w.left) {
 			w.right.color = black
 			w.color = red
 			t.leftRotate(w)
-			w = x.parent.left
+			w = parent.left
 		}
-		w.color = x.parent.color
-		x.parent.color = black
+		w.color = parent.color
+		parent.color = black
 		w.left.color = black
-		t.rightRotate(x.parent)
+		t.rightRotate(parent)
 		x = t.root
 
 	}
-	x.color = black
+	if x != t.nilNode {
+		x.color = black
+	}
 }
 
 func (t *Tree[V]) min(n *node[V]) *node[V] {
@@ -348,8 +645,16 @@ func (t *Tree[V]) min(n *node[V]) *node[V] {
 	return n
 }
 
+func (t *Tree[V]) max(n *node[V]) *node[V] {
+	for n.right != t.nilNode {
+		n = n.right
+	}
+
+	return n
+}
+
 func (t *Tree[V]) getNewNode(key V, value any) *node[V] {
-	return &node[V]{element: element[V]{
+	n := &node[V]{element: element[V]{
 		key:   key,
 		value: value,
 	},
@@ -358,6 +663,15 @@ func (t *Tree[V]) getNewNode(key V, value any) *node[V] {
 		right:  t.nilNode,
 		parent: t.nilNode,
 	}
+	if t.merkle || t.aug != nil {
+		n.ext = &nodeExt{}
+	}
+	if t.merkle {
+		n.ext.merkle.self = t.elementHash(key, value)
+	}
+	t.pull(n)
+
+	return n
 }
 
 func (t *Tree[V]) isRoot(n *node[V]) bool {
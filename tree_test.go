@@ -1,6 +1,7 @@
 package rbtree
 
 import (
+	"math/rand"
 	"reflect"
 	"testing"
 
@@ -84,6 +85,8 @@ func TestTree_NewWithElement(t *testing.T) {
 					parent: nilNodeInt,
 				},
 				nilNode: nilNodeInt,
+				size:    1,
+				bytes:   elementBytes[int](1, nil),
 			},
 		},
 		{
@@ -101,6 +104,8 @@ func TestTree_NewWithElement(t *testing.T) {
 					parent: nilNodeInt,
 				},
 				nilNode: nilNodeInt,
+				size:    1,
+				bytes:   elementBytes[int](15, 15),
 			},
 		},
 	}
@@ -197,6 +202,12 @@ func TestTree_Exist(t1 *testing.T) {
 			args: args[int]{key: 1},
 			want: false,
 		},
+		{
+			name: "empty tree - zero key",
+			t:    getTree([]int{}),
+			args: args[int]{key: 0},
+			want: false,
+		},
 		{
 			name: "tree with one element - not found",
 			t:    getTree([]int{15}),
@@ -399,6 +410,60 @@ func TestTree_Insert(t1 *testing.T) {
 	}
 }
 
+// Insert of existing key replaces its value: keys of tree are unique (earlier versions kept duplicates)
+func TestTree_Insert_existing_key(t1 *testing.T) {
+	t := getTree([]int{15, 25, 10})
+	t.Insert(25, "new")
+	t.Insert(25, "newer")
+
+	if got, _ := t.GetValue(25); got != "newer" {
+		t1.Errorf("GetValue() = %v, want newer", got)
+	}
+	var keys []int
+	t.Ascend(func(key int, value any) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if !reflect.DeepEqual(keys, []int{10, 15, 25}) || t.Len() != 3 {
+		t1.Errorf("tree has keys %v and Len() = %v, want [10 15 25] and 3", keys, t.Len())
+	}
+	checkTreeProperties(t1, t)
+
+	t.Delete(25)
+	if t.Exists(25) || t.Len() != 2 {
+		t1.Errorf("Delete() left duplicate of key 25, Len() = %v", t.Len())
+	}
+}
+
+func TestTree_Ascend(t1 *testing.T) {
+	type testCase struct {
+		name   string
+		t      *Tree[int]
+		lo, hi int
+		want   []int
+	}
+
+	tests := []testCase{
+		{name: "empty tree", t: getTree([]int{}), lo: 0, hi: 100, want: nil},
+		{name: "all elements", t: getTree([]int{22, 8, 4, 15, 1}), lo: 0, hi: 100, want: []int{1, 4, 8, 15, 22}},
+		{name: "subrange", t: getTree([]int{22, 8, 4, 15, 1}), lo: 4, hi: 22, want: []int{4, 8, 15}},
+		{name: "bounds between keys", t: getTree([]int{22, 8, 4, 15, 1}), lo: 5, hi: 16, want: []int{8, 15}},
+		{name: "empty range", t: getTree([]int{22, 8, 4, 15, 1}), lo: 16, hi: 20, want: nil},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			var got []int
+			tt.t.AscendRange(tt.lo, tt.hi, func(key int, value any) bool {
+				got = append(got, key)
+				return true
+			})
+			if !reflect.DeepEqual(got, tt.want) {
+				t1.Errorf("AscendRange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestTree_Insert_case_2(t1 *testing.T) {
 	t := getTree([]int{11, 9, 18, 8, 10})
 
@@ -787,6 +852,123 @@ func TestTree_Delete_case_4_delete_black_node_with_red_brother(t1 *testing.T) {
 	}
 }
 
+func TestTree_Len(t1 *testing.T) {
+	t := getTree([]int{22, 8, 4, 15, 1})
+	t.Insert(8, 80)
+	t.Delete(22)
+	t.Delete(100)
+
+	if got := t.Len(); got != 4 {
+		t1.Errorf("Len() = %v, want 4", got)
+	}
+	if got := NewWithElement(1, 1).Len(); got != 1 {
+		t1.Errorf("NewWithElement() Len() = %v, want 1", got)
+	}
+}
+
+func TestTree_SplitJoin(t1 *testing.T) {
+	for n := 0; n < 40; n++ {
+		var elements []int
+		for i := 0; i < n; i++ {
+			elements = append(elements, i*2)
+		}
+		t := getTree(elements)
+
+		right := t.Split(n)
+		checkTreeProperties(t1, t)
+		checkTreeProperties(t1, right)
+		if t.Len()+right.Len() != n || t.Len() > 0 && t.Max() >= n || right.Len() > 0 && right.Min() < n {
+			t1.Fatalf("Split(%v) = %v and %v elements", n, t.Len(), right.Len())
+		}
+
+		if err := right.Join(t); err == nil && t.Len() > 0 {
+			t1.Fatalf("Join() of smaller keys without error")
+		}
+		if err := t.Join(right); err != nil {
+			t1.Fatalf("Join() error = %v", err)
+		}
+		checkTreeProperties(t1, t)
+		if t.Len() != n || right.Len() != 0 {
+			t1.Fatalf("Join() = %v and %v elements", t.Len(), right.Len())
+		}
+		for _, key := range elements {
+			if !t.Exists(key) {
+				t1.Fatalf("element %v lost after Split and Join", key)
+			}
+		}
+
+		// tree is still correct for changes after rebuilding
+		t.Insert(-1, -1)
+		t.Delete(0)
+		checkTreeProperties(t1, t)
+	}
+}
+
+func TestTree_InsertDelete_random(t1 *testing.T) {
+	t := New[int]()
+	want := map[int]int{}
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 3000; i++ {
+		key := rng.Intn(200)
+		if rng.Intn(3) == 0 {
+			t.Delete(key)
+			delete(want, key)
+		} else {
+			t.Insert(key, i)
+			want[key] = i
+		}
+		checkTreeProperties(t1, t)
+	}
+
+	for key, value := range want {
+		if got, err := t.GetValue(key); err != nil || got != value {
+			t1.Errorf("GetValue(%v) = %v, %v, want %v", key, got, err, value)
+		}
+	}
+}
+
+// checkTreeProperties checks red-black properties, parent links, key order and size of tree
+func checkTreeProperties[V constraints.Ordered](t *testing.T, tree *Tree[V]) {
+	if tree.root.color != black {
+		t.Fatalf("root is not black")
+	}
+	if tree.root != tree.nilNode && tree.root.parent != tree.nilNode {
+		t.Fatalf("root has parent")
+	}
+
+	count := 0
+	var check func(n *node[V]) int
+	check = func(n *node[V]) int {
+		if n == tree.nilNode {
+			return 1
+		}
+		count++
+		if n.color == red && (n.left.color == red || n.right.color == red) {
+			t.Fatalf("red node %v has red child", n.element.key)
+		}
+		if n.left != tree.nilNode && (n.left.parent != n || n.left.element.key >= n.element.key) ||
+			n.right != tree.nilNode && (n.right.parent != n || n.right.element.key <= n.element.key) {
+			t.Fatalf("node %v has wrong children", n.element.key)
+		}
+
+		left := check(n.left)
+		if right := check(n.right); left != right {
+			t.Fatalf("node %v has different black heights %v and %v", n.element.key, left, right)
+		}
+		if n.color == black {
+			left++
+		}
+
+		return left
+	}
+	check(tree.root)
+
+	if count != tree.Len() {
+		t.Fatalf("tree has %v elements, Len() = %v", count, tree.Len())
+	}
+}
+
 func checkNode[V constraints.Ordered](t *testing.T, vn *validNode[V]) {
 	if vn == nil {
 		return
@@ -853,3 +1035,25 @@ func colorToString(c color) string {
 		return "unknown"
 	}
 }
+func BenchmarkTree_Insert(b *testing.B) {
+	keys := rand.New(rand.NewSource(1)).Perm(b.N)
+	t := New[int]()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for _, key := range keys {
+		t.Insert(key, key)
+	}
+}
+
+func BenchmarkTree_Delete(b *testing.B) {
+	keys := rand.New(rand.NewSource(1)).Perm(b.N)
+	t := New[int]()
+	for _, key := range keys {
+		t.Insert(key, key)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for _, key := range keys {
+		t.Delete(key)
+	}
+}
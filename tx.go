@@ -0,0 +1,175 @@
+package rbtree
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+// ErrTxDone is returned by Commit and Rollback of finished transaction
+var ErrTxDone = errors.New("transaction has already been committed or rolled back")
+
+// Tx is a transaction of tree.
+// Changes of transaction are staged and visible only through transaction
+// until Commit applies all of them to tree at once.
+// Tx doesn't lock tree: changes made to tree after Begin are overwritten by Commit
+type Tx[V constraints.Ordered] struct {
+	tree *Tree[V]
+	ops  map[V]txOp
+	done bool
+}
+
+// txOp is staged change of one element
+type txOp struct {
+	value   any
+	deleted bool
+}
+
+// txUndo is state of one element before Commit changed it
+type txUndo[V constraints.Ordered] struct {
+	key     V
+	value   any
+	existed bool
+}
+
+// Begin is a function for starting transaction of tree
+func (t *Tree[V]) Begin() *Tx[V] {
+	return &Tx[V]{
+		tree: t,
+		ops:  map[V]txOp{},
+	}
+}
+
+// Insert is a function for staging inserting element into tree.
+// If element with the same key exists, its value will be replaced.
+// - param key should be `ordered type` (`int`, `string`, `float` etc.)
+// - param value can be any type
+func (tx *Tx[V]) Insert(key V, value any) {
+	tx.ops[key] = txOp{value: value}
+}
+
+// Delete is a function for staging deleting element from tree
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (tx *Tx[V]) Delete(key V) {
+	tx.ops[key] = txOp{deleted: true}
+}
+
+// Exists is a function for searching element in tree with staged changes.
+// If element exists - return true, else - false
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (tx *Tx[V]) Exists(key V) bool {
+	if op, ok := tx.ops[key]; ok {
+		return !op.deleted
+	}
+
+	return tx.tree.Exists(key)
+}
+
+// GetValue is a function for searching element in tree with staged changes and returning value of this element
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (tx *Tx[V]) GetValue(key V) (any, error) {
+	op, ok := tx.ops[key]
+	if !ok {
+		return tx.tree.GetValue(key)
+	}
+
+	if op.deleted {
+		return nil, errors.New(fmt.Sprintf("element with key %v not found", key))
+	}
+
+	return op.value, nil
+}
+
+// Commit is a function for applying all staged changes to tree: all changes are applied or none.
+// Staged values are validated before the first change of tree (values of tree with merkle hashes
+// should be encodable by its codecs), invalid value returns error, tree isn't changed and transaction stays open.
+// If change of tree panics (e.g. in aggregate function of AggregateTree), applied changes are rolled back
+// before panic is propagated. Changes are applied under one write access of tree,
+// so readers checked by WithAccessCheck never see tree half-updated.
+func (tx *Tx[V]) Commit() error {
+	if tx.done {
+		return ErrTxDone
+	}
+
+	keys := make([]V, 0, len(tx.ops))
+	for key := range tx.ops {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	tx.tree.guard.startWrite()
+	defer tx.tree.guard.endWrite()
+
+	if err := tx.validate(keys); err != nil {
+		return err
+	}
+	tx.done = true
+
+	undo := make([]txUndo[V], 0, len(keys))
+	defer func() {
+		if r := recover(); r != nil {
+			for i := len(undo) - 1; i >= 0; i-- {
+				if u := undo[i]; u.existed {
+					tx.tree.put(u.key, u.value)
+				} else {
+					tx.tree.remove(u.key)
+				}
+			}
+			panic(r)
+		}
+	}()
+
+	for _, key := range keys {
+		old, existed := tx.tree.valueOf(key)
+		undo = append(undo, txUndo[V]{key: key, value: old, existed: existed})
+		if op := tx.ops[key]; op.deleted {
+			tx.tree.remove(key)
+		} else {
+			tx.tree.put(key, op.value)
+		}
+	}
+	tx.ops = map[V]txOp{}
+
+	return nil
+}
+
+// Rollback is a function for discarding all staged changes
+func (tx *Tx[V]) Rollback() error {
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.done = true
+	tx.ops = map[V]txOp{}
+
+	return nil
+}
+
+// validate - internal function for checking that staged values can be put into tree without errors
+func (tx *Tx[V]) validate(keys []V) error {
+	if !tx.tree.merkle {
+		return nil
+	}
+
+	for _, key := range keys {
+		op := tx.ops[key]
+		if op.deleted {
+			continue
+		}
+		if _, err := appendElement(nil, tx.tree.getCodecs(), key, op.value); err != nil {
+			return errors.New(fmt.Sprintf("can't commit element with key %v: %v", key, err))
+		}
+	}
+
+	return nil
+}
+
+// valueOf - internal function for getting value of element and true if element exists
+func (t *Tree[V]) valueOf(key V) (any, bool) {
+	if n := t.search(key); n != nil {
+		return n.element.value, true
+	}
+
+	return nil, false
+}
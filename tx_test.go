@@ -0,0 +1,117 @@
+package rbtree
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTx_Commit(t1 *testing.T) {
+	t := getTree([]int{22, 8, 4})
+	tx := t.Begin()
+	tx.Insert(15, 15)
+	tx.Insert(8, 80)
+	tx.Delete(22)
+	tx.Delete(100)
+
+	if !tx.Exists(15) || tx.Exists(22) {
+		t1.Errorf("staged changes aren't visible through transaction")
+	}
+	if value, err := tx.GetValue(8); err != nil || value != 80 {
+		t1.Errorf("tx.GetValue(8) = %v, %v, want 80", value, err)
+	}
+	if _, err := tx.GetValue(22); err == nil {
+		t1.Errorf("tx.GetValue() of deleted element without error")
+	}
+	if value, _ := tx.GetValue(4); value != 4 {
+		t1.Errorf("tx.GetValue(4) = %v, want 4", value)
+	}
+	if t.Exists(15) || !t.Exists(22) {
+		t1.Errorf("staged changes are visible in tree before Commit")
+	}
+
+	if err := tx.Commit(); err != nil {
+		t1.Fatalf("Commit() error = %v", err)
+	}
+	if value, _ := t.GetValue(8); value != 80 || !t.Exists(15) || t.Exists(22) || t.Len() != 3 {
+		t1.Errorf("changes weren't applied by Commit()")
+	}
+	checkTreeProperties(t1, t)
+
+	if err := tx.Commit(); !errors.Is(err, ErrTxDone) {
+		t1.Errorf("second Commit() error = %v, want %v", err, ErrTxDone)
+	}
+	if err := tx.Rollback(); !errors.Is(err, ErrTxDone) {
+		t1.Errorf("Rollback() after Commit() error = %v, want %v", err, ErrTxDone)
+	}
+}
+
+func TestTx_Rollback(t1 *testing.T) {
+	t := getTree([]int{22, 8, 4})
+	tx := t.Begin()
+	tx.Insert(15, 15)
+	tx.Delete(22)
+
+	if err := tx.Rollback(); err != nil {
+		t1.Fatalf("Rollback() error = %v", err)
+	}
+	if !treeEquals(t, getTree([]int{22, 8, 4})) {
+		t1.Errorf("tree was changed by rolled back transaction")
+	}
+	if err := tx.Commit(); !errors.Is(err, ErrTxDone) {
+		t1.Errorf("Commit() after Rollback() error = %v, want %v", err, ErrTxDone)
+	}
+}
+
+func TestTx_Commit_invalidValue(t1 *testing.T) {
+	t := New[int](WithMerkle())
+	t.Insert(1, 1)
+	want := t.RootHash()
+
+	tx := t.Begin()
+	tx.Insert(0, 0)
+	tx.Insert(2, make(chan int)) // value can't be encoded for merkle hash
+	if err := tx.Commit(); err == nil {
+		t1.Fatalf("Commit() with value which can't be encoded error = nil")
+	}
+	if t.Len() != 1 || t.RootHash() != want {
+		t1.Errorf("tree was changed by failed Commit(), Len() = %v", t.Len())
+	}
+
+	// transaction stays open after error
+	tx.Delete(2)
+	if err := tx.Commit(); err != nil || !t.Exists(0) || t.Exists(2) {
+		t1.Errorf("Commit() after fix error = %v", err)
+	}
+}
+
+func TestTx_Commit_panic(t1 *testing.T) {
+	t := newSumCountTree()
+	t.Insert(1, 1)
+	t.Insert(5, 5)
+
+	tx := t.Begin()
+	tx.Insert(0, 0)
+	tx.Delete(1)
+	tx.Insert(5, 50)
+	tx.Insert(9, "not int") // combine of aggregates panics
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t1.Errorf("Commit() didn't propagate panic")
+			}
+		}()
+		tx.Commit()
+	}()
+
+	var got []element[int]
+	t.Ascend(func(key int, value any) bool {
+		got = append(got, element[int]{key: key, value: value})
+		return true
+	})
+	if want := []element[int]{{key: 1, value: 1}, {key: 5, value: 5}}; !reflect.DeepEqual(got, want) {
+		t1.Errorf("tree after panic in Commit() = %v, want %v", got, want)
+	}
+	checkTreeProperties(t1, t.Tree)
+	checkAggregates(t1, t.Tree, t.root)
+}
@@ -0,0 +1,164 @@
+package rbtree
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+// VersionedTree is a multi-version tree: every change creates new version of tree
+// and old values are kept, so tree can be read as of any past version.
+// Every key of tree holds history of its values ordered by version
+type VersionedTree[V constraints.Ordered] struct {
+	tree      *Tree[V]
+	version   uint64
+	compacted uint64
+}
+
+// versionedValue is the value of key since version (till next versioned value of key)
+type versionedValue struct {
+	version uint64
+	value   any
+	deleted bool
+}
+
+// NewVersionedTree is a function for creation empty multi-version tree. Version of empty tree is 0.
+// - param should be `ordered type` (`int`, `string`, `float` etc)
+func NewVersionedTree[V constraints.Ordered]() *VersionedTree[V] {
+	return &VersionedTree[V]{
+		tree: New[V](),
+	}
+}
+
+// Version is a function for getting current version of tree
+func (t *VersionedTree[V]) Version() uint64 {
+	return t.version
+}
+
+// Insert is a function for inserting element into tree. It returns new version of tree.
+// If element with the same key exists, its value is replaced in new version.
+// - param key should be `ordered type` (`int`, `string`, `float` etc.)
+// - param value can be any type
+func (t *VersionedTree[V]) Insert(key V, value any) uint64 {
+	t.version++
+	t.add(key, versionedValue{version: t.version, value: value})
+
+	return t.version
+}
+
+// Delete is a function for deleting element from tree. It returns new version of tree.
+// If element doesn't exist, version isn't changed.
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *VersionedTree[V]) Delete(key V) uint64 {
+	if _, err := t.GetValue(key); err != nil {
+		return t.version
+	}
+
+	t.version++
+	t.add(key, versionedValue{version: t.version, deleted: true})
+
+	return t.version
+}
+
+// GetValue is a function for searching element in current version of tree and returning value of this element
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+func (t *VersionedTree[V]) GetValue(key V) (any, error) {
+	return t.GetAt(key, t.version)
+}
+
+// GetAt is a function for searching element in past version of tree and returning value of this element
+// - param key should be `ordered type` (`int`, `string`, `float` etc)
+// - param version is version of tree, it shouldn't be less than version passed to Compact
+func (t *VersionedTree[V]) GetAt(key V, version uint64) (any, error) {
+	if version < t.compacted {
+		return nil, errors.New(fmt.Sprintf("version %v is compacted", version))
+	}
+
+	var history []versionedValue
+	if n := t.tree.search(key); n != nil {
+		history = n.element.value.([]versionedValue)
+	}
+	if v, ok := valueAt(history, version); ok {
+		return v, nil
+	}
+
+	return nil, errors.New(fmt.Sprintf("element with key %v not found", key))
+}
+
+// RangeAt is a function for iterating over elements with keys in range [lo, hi) of past version of tree in key order.
+// Iteration stops when fn returns false.
+// It returns error if version is less than version passed to Compact.
+// - params lo and hi should be `ordered type` (`int`, `string`, `float` etc)
+// - param version is version of tree
+func (t *VersionedTree[V]) RangeAt(lo, hi V, version uint64, fn func(key V, value any) bool) error {
+	if version < t.compacted {
+		return errors.New(fmt.Sprintf("version %v is compacted", version))
+	}
+
+	t.tree.AscendRange(lo, hi, func(key V, value any) bool {
+		v, ok := valueAt(value.([]versionedValue), version)
+		return !ok || fn(key, v)
+	})
+
+	return nil
+}
+
+// Compact is a function for discarding history which is needed only for versions less than before.
+// After compaction tree can't be read as of versions less than before.
+func (t *VersionedTree[V]) Compact(before uint64) {
+	if before > t.version {
+		before = t.version
+	}
+	if before <= t.compacted {
+		return
+	}
+	t.compacted = before
+
+	var deleted []V
+	t.tree.Ascend(func(key V, value any) bool {
+		history := value.([]versionedValue)
+		// the last value before version is still visible at version
+		i := sort.Search(len(history), func(i int) bool { return history[i].version > before })
+		if i > 0 {
+			i--
+		}
+		// deletion is not needed if there are no older values
+		for i < len(history) && history[i].deleted {
+			i++
+		}
+
+		if i == len(history) {
+			deleted = append(deleted, key)
+			return true
+		}
+		t.tree.search(key).element.value = append([]versionedValue(nil), history[i:]...)
+
+		return true
+	})
+
+	for _, key := range deleted {
+		t.tree.Delete(key)
+	}
+}
+
+// add - internal function for appending new value to history of key
+func (t *VersionedTree[V]) add(key V, v versionedValue) {
+	n := t.tree.search(key)
+	if n == nil {
+		t.tree.Insert(key, []versionedValue{v})
+		return
+	}
+	n.element.value = append(n.element.value.([]versionedValue), v)
+}
+
+// valueAt - internal function for searching value visible at version in history ordered by version
+func valueAt(history []versionedValue, version uint64) (any, bool) {
+	i := sort.Search(len(history), func(i int) bool { return history[i].version > version })
+	if i == 0 || history[i-1].deleted {
+		return nil, false
+	}
+
+	return history[i-1].value, true
+}
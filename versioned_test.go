@@ -0,0 +1,92 @@
+package rbtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVersionedTree_GetAt(t1 *testing.T) {
+	t := NewVersionedTree[int]()
+	v1 := t.Insert(8, "a")
+	v2 := t.Insert(4, "b")
+	v3 := t.Insert(8, "c")
+	v4 := t.Delete(4)
+	if v5 := t.Delete(100); v5 != v4 {
+		t1.Errorf("Delete() of missing element changed version %v to %v", v4, v5)
+	}
+
+	type testCase struct {
+		name    string
+		key     int
+		version uint64
+		want    any
+		wantErr bool
+	}
+	tests := []testCase{
+		{name: "before insert", key: 8, version: 0, wantErr: true},
+		{name: "first value", key: 8, version: v1, want: "a"},
+		{name: "first value in later version", key: 8, version: v2, want: "a"},
+		{name: "updated value", key: 8, version: v3, want: "c"},
+		{name: "before deleting", key: 4, version: v3, want: "b"},
+		{name: "after deleting", key: 4, version: v4, wantErr: true},
+		{name: "future version", key: 8, version: 100, want: "c"},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			got, err := t.GetAt(tt.key, tt.version)
+			if (err != nil) != tt.wantErr {
+				t1.Errorf("GetAt() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t1.Errorf("GetAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionedTree_RangeAtCompact(t1 *testing.T) {
+	t := NewVersionedTree[int]()
+	for i := 1; i <= 5; i++ {
+		t.Insert(i, i)
+	}
+	v5 := t.Version()
+	t.Delete(2)
+	t.Insert(3, 30)
+	t.Delete(5)
+	v8 := t.Version()
+
+	rangeAt := func(version uint64) []any {
+		var got []any
+		if err := t.RangeAt(0, 5, version, func(key int, value any) bool {
+			got = append(got, value)
+			return true
+		}); err != nil {
+			t1.Fatalf("RangeAt(%v) error = %v", version, err)
+		}
+		return got
+	}
+	if got, want := rangeAt(v5), []any{1, 2, 3, 4}; !reflect.DeepEqual(got, want) {
+		t1.Errorf("RangeAt(%v) = %v, want %v", v5, got, want)
+	}
+	if got, want := rangeAt(v8), []any{1, 30, 4}; !reflect.DeepEqual(got, want) {
+		t1.Errorf("RangeAt(%v) = %v, want %v", v8, got, want)
+	}
+
+	t.Compact(v8)
+	if _, err := t.GetAt(1, v5); err == nil {
+		t1.Errorf("GetAt() of compacted version without error")
+	}
+	if err := t.RangeAt(0, 5, v5, func(key int, value any) bool { return true }); err == nil {
+		t1.Errorf("RangeAt() of compacted version without error")
+	}
+	if got, want := rangeAt(v8), []any{1, 30, 4}; !reflect.DeepEqual(got, want) {
+		t1.Errorf("RangeAt(%v) after Compact() = %v, want %v", v8, got, want)
+	}
+	if t.tree.Len() != 3 {
+		t1.Errorf("deleted keys weren't discarded by Compact(), %v keys", t.tree.Len())
+	}
+	if history := t.tree.search(3).element.value.([]versionedValue); len(history) != 1 {
+		t1.Errorf("old values weren't discarded by Compact(), %v values", len(history))
+	}
+}